@@ -0,0 +1,370 @@
+// Package cli implements kubetbe's non-interactive subcommands. They reuse
+// the kubectl package and print a table, JSON or YAML, so they work in
+// scripts, CI and runbooks without starting the terminal UI.
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"kubetbe/config"
+	"kubetbe/kubectl"
+	"kubetbe/msg"
+	"kubetbe/utils"
+)
+
+// Exit codes of Run. Scripts depend on them, so they never change meaning.
+const (
+	exitOK      = 0
+	exitError   = 1 // anything not covered below, e.g. output could not be written
+	exitUsage   = 2 // unknown command, flag or malformed argument
+	exitNoMatch = 3 // the command ran but found nothing
+	exitKubectl = 4 // kubectl failed or could not be run
+	exitAuth    = 5 // the cluster rejected the credentials or RBAC denied the request
+
+	exitInterrupted = 130 // stopped by SIGINT, SIGTERM or SIGHUP, as shells report ^C
+)
+
+// Env is what a subcommand runs with.
+type Env struct {
+	Context  context.Context // kubectl calls are killed when it ends
+	Contexts []string        // multi-cluster mode: list namespaces from all of these
+	Flags    kubectl.Flags   // kubectl's global flags for every call
+	Stdout   io.Writer
+	Stderr   io.Writer
+}
+
+// command is one subcommand.
+type command struct {
+	name    string
+	args    string // synopsis of the arguments after the name
+	summary string
+	run     func(env *Env, fs *flag.FlagSet, args []string) error
+}
+
+// commands are listed in this order by Usage.
+var commands = []command{
+	{"ns", "[flags] [filter]", "List namespaces with pod counts, best match first", runNamespaces},
+	{"find-ip", "[flags] <ip|cidr>", "Find services, pods, endpoints and nodes using an IP", runFindIP},
+	{"logs", "[flags] <namespace> <pod>", "Print the logs of a pod", runLogs},
+	{"version", "[flags]", "Print version and build information", runVersion},
+}
+
+// usageError is a mistake in how a subcommand was called.
+type usageError struct{ error }
+
+func usagef(format string, args ...any) error {
+	return usageError{fmt.Errorf(format, args...)}
+}
+
+// noMatchError means a command ran fine but found nothing.
+type noMatchError struct{ error }
+
+// kubectlError is a failure of kubectl itself or of the cluster behind it.
+type kubectlError struct{ error }
+
+// IsCommand reports whether name is a subcommand. Anything else given as
+// the first argument is a namespace filter for the terminal UI.
+func IsCommand(name string) bool {
+	return lookup(name) != nil
+}
+
+func lookup(name string) *command {
+	for i := range commands {
+		if commands[i].name == name {
+			return &commands[i]
+		}
+	}
+	return nil
+}
+
+// Usage writes the list of subcommands.
+func Usage(w io.Writer) {
+	fmt.Fprintf(w, "Commands:\n")
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	for _, c := range commands {
+		fmt.Fprintf(tw, "  %s %s\t%s\n", c.name, c.args, c.summary)
+	}
+	tw.Flush()
+}
+
+// Run runs the subcommand named by args[0], reports any error on stderr
+// and returns the process exit code.
+func Run(env *Env, args []string) int {
+	c := lookup(args[0])
+	if c == nil {
+		fmt.Fprintf(env.Stderr, "Error: unknown command %q\n", args[0])
+		return exitUsage
+	}
+	err := c.run(env, c.flagSet(env), args[1:])
+	var (
+		usage   usageError
+		noMatch noMatchError
+		failed  kubectlError
+	)
+	switch {
+	case err == nil:
+		return exitOK
+	case env.Context.Err() != nil:
+		return exitInterrupted
+	case errors.Is(err, flag.ErrHelp):
+		return exitOK
+	case errors.As(err, &usage):
+		fmt.Fprintf(env.Stderr, "Error: %v\nUsage: kubetbe %s %s\n", err, c.name, c.args)
+		return exitUsage
+	case errors.As(err, &noMatch):
+		fmt.Fprintln(env.Stderr, err)
+		return exitNoMatch
+	case errors.As(err, &failed):
+		fmt.Fprintf(env.Stderr, "Error: %v\n", err)
+		if e := kubectl.Explain(err); e.Kind != kubectl.ErrUnknown {
+			fmt.Fprintf(env.Stderr, "%s\nFix: %s\n", e.Summary, e.Fix)
+		}
+		if kubectl.IsAuthError(err) {
+			return exitAuth
+		}
+		return exitKubectl
+	default:
+		fmt.Fprintf(env.Stderr, "Error: %v\n", err)
+		return exitError
+	}
+}
+
+// parseFlags parses flags anywhere among args, so "logs shop web-1 -f" and
+// "logs -f shop web-1" both work, and returns the remaining arguments.
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, usageError{err}
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// flagSet returns an empty flag set for c that reports errors to Run
+// instead of exiting.
+func (c *command) flagSet(env *Env) *flag.FlagSet {
+	fs := flag.NewFlagSet(c.name, flag.ContinueOnError)
+	fs.SetOutput(env.Stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(env.Stderr, "Usage: kubetbe %s %s\n\n%s\n", c.name, c.args, c.summary)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+func runVersion(env *Env, fs *flag.FlagSet, args []string) error {
+	format := outputFlag(fs)
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) > 0 {
+		return usagef("version takes no arguments")
+	}
+	if err := checkFormat(*format); err != nil {
+		return err
+	}
+	info := buildInfo()
+	return write(env.Stdout, *format, info, func(w io.Writer) error {
+		_, err := fmt.Fprint(w, info)
+		return err
+	})
+}
+
+func runNamespaces(env *Env, fs *flag.FlagSet, args []string) error {
+	favoritesOnly := fs.Bool("favorites", false, "list only starred namespaces")
+	format := outputFlag(fs)
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) > 1 {
+		return usagef("expected at most one filter, got %d arguments", len(args))
+	}
+	if err := checkFormat(*format); err != nil {
+		return err
+	}
+	filter := ""
+	if len(args) == 1 {
+		filter = args[0]
+	}
+
+	all, err := listNamespaces(env)
+	if err != nil {
+		return kubectlError{err}
+	}
+	// Rank like the namespace view does; a broken state file only loses
+	// the favorites ordering
+	state, err := config.LoadState()
+	if err != nil {
+		fmt.Fprintf(env.Stderr, "Warning: %v\n", err)
+	}
+	names := make([]string, len(all))
+	for i, ns := range all {
+		names[i] = ns.Name
+	}
+	favorite := func(i int) bool { return state.IsFavorite(names[i]) }
+	var namespaces []msg.NamespaceInfo
+	for _, i := range utils.FuzzyRank(filter, names, favorite) {
+		if !*favoritesOnly || favorite(i) {
+			namespaces = append(namespaces, all[i])
+		}
+	}
+
+	records := make([]namespaceRecord, len(namespaces))
+	for i, ns := range namespaces {
+		records[i] = newNamespaceRecord(ns, state.IsFavorite(ns.Name))
+	}
+	err = write(env.Stdout, *format, records, func(w io.Writer) error {
+		if len(records) == 0 {
+			return nil
+		}
+		now := time.Now()
+		tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+		header := "NAME\tSTATUS\tAGE\tRUNNING\tPENDING\tFAILING"
+		if len(env.Contexts) > 0 {
+			header = "NAME\tCONTEXT\tSTATUS\tAGE\tRUNNING\tPENDING\tFAILING"
+		}
+		fmt.Fprintln(tw, header)
+		for _, r := range records {
+			age := "-"
+			if r.Created != nil {
+				age = utils.HumanDuration(now.Sub(*r.Created))
+			}
+			running, pending, failing := "?", "?", "?"
+			if r.PodsKnown {
+				running, pending, failing = fmt.Sprint(r.Running), fmt.Sprint(r.Pending), fmt.Sprint(r.Failing)
+			}
+			name := r.Name
+			if len(env.Contexts) > 0 {
+				name += "\t" + r.Context
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", name, r.Status, age, running, pending, failing)
+		}
+		return tw.Flush()
+	})
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return noMatchError{fmt.Errorf("no namespaces match %q", filter)}
+	}
+	return nil
+}
+
+// listNamespaces lists the namespaces of the current context, or of every
+// context in multi-cluster mode. There a degraded cluster is only a
+// warning, unless no cluster answered at all.
+func listNamespaces(env *Env) ([]msg.NamespaceInfo, error) {
+	if len(env.Contexts) == 0 {
+		return kubectl.GetNamespaces(env.Context, env.Flags)
+	}
+	namespaces, clusters := kubectl.GetNamespacesMulti(env.Context, env.Flags, env.Contexts)
+	var errs []error
+	for _, c := range clusters {
+		if c.Err != nil {
+			fmt.Fprintf(env.Stderr, "Warning: context %s is degraded: %v\n", c.Context, c.Err)
+			errs = append(errs, fmt.Errorf("%s: %w", c.Context, c.Err))
+		}
+	}
+	if len(errs) == len(clusters) {
+		return nil, errors.Join(errs...)
+	}
+	return namespaces, nil
+}
+
+func runFindIP(env *Env, fs *flag.FlagSet, args []string) error {
+	format := outputFlag(fs)
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return usagef("expected one IP address or CIDR block")
+	}
+	if _, _, err := net.ParseCIDR(args[0]); net.ParseIP(args[0]) == nil && err != nil {
+		return usagef("%q is not an IP address or CIDR block", args[0])
+	}
+	if err := checkFormat(*format); err != nil {
+		return err
+	}
+
+	matches, err := kubectl.LookupIP(env.Context, env.Flags, args[0])
+	incomplete := errors.Is(err, kubectl.ErrIncomplete)
+	if err != nil && !incomplete {
+		return kubectlError{err}
+	}
+	if incomplete {
+		fmt.Fprintf(env.Stderr, "Warning: %v\n", err)
+	}
+	records := make([]matchRecord, len(matches))
+	for i, m := range matches {
+		records[i] = newMatchRecord(m)
+	}
+	err = write(env.Stdout, *format, records, func(w io.Writer) error {
+		if len(records) == 0 {
+			return nil
+		}
+		tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(tw, "KIND\tNAMESPACE\tNAME\tFIELD\tADDRESS\tOWNER")
+		for _, r := range records {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+				r.Kind, utils.OrDash(r.Namespace), r.Name, r.Field, r.Address, utils.OrDash(r.Owner))
+		}
+		return tw.Flush()
+	})
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		if incomplete {
+			// What could not be listed may well be what uses the address
+			return kubectlError{err}
+		}
+		return noMatchError{fmt.Errorf("nothing uses %s", args[0])}
+	}
+	return nil
+}
+
+func runLogs(env *Env, fs *flag.FlagSet, args []string) error {
+	tail := fs.Int("tail", -1, "number of recent lines to show, -1 for all")
+	since := fs.Duration("since", 0, "only show lines newer than this, e.g. 10m")
+	timestamps := fs.Bool("timestamps", false, "prefix each line with its timestamp")
+	follow := fs.Bool("f", false, "keep streaming new lines")
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 2 {
+		return usagef("expected a namespace and a pod")
+	}
+
+	opts := kubectl.LogOptions{Tail: *tail, Timestamps: *timestamps, Since: *since, Follow: *follow}
+	if err := kubectl.Logs(env.Context, env.Flags, args[0], args[1], opts, env.Stdout); err != nil {
+		return kubectlError{fmt.Errorf("failed to get logs of %s/%s: %w", args[0], args[1], err)}
+	}
+	return nil
+}
+
+// NewEnv returns an Env for cfg writing to the process's stdout and
+// stderr, whose kubectl calls end with ctx.
+func NewEnv(ctx context.Context, cfg *config.Config) *Env {
+	return &Env{Context: ctx, Contexts: cfg.Contexts, Flags: kubectl.CurrentFlags(), Stdout: os.Stdout, Stderr: os.Stderr}
+}
@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeKubectl puts a kubectl running the shell script body first on $PATH
+// for the rest of the test.
+func fakeKubectl(t *testing.T, body string) {
+	t.Helper()
+	dir := t.TempDir()
+	script := "#!/bin/sh\n" + body + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "kubectl"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestLogsExitCodes(t *testing.T) {
+	tests := []struct {
+		name       string
+		kubectl    string
+		wantCode   int
+		wantStderr string
+	}{
+		{"ok", "echo 'GET /healthz 200'", exitOK, ""},
+		{
+			"expired credentials",
+			"echo 'error: You must be logged in to the server (Unauthorized)' >&2; exit 1",
+			exitAuth,
+			"credentials for this cluster are missing or have expired",
+		},
+		{
+			"rbac denied",
+			`echo 'Error from server (Forbidden): pods "web-0" is forbidden: User "jane" cannot get resource "pods/log" in API group "" in the namespace "shop"' >&2; exit 1`,
+			exitAuth,
+			"RBAC denied the request",
+		},
+		{
+			"pod gone",
+			`echo 'Error from server (NotFound): pods "web-0" not found' >&2; exit 1`,
+			exitKubectl,
+			`pods "web-0" not found`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeKubectl(t, tt.kubectl)
+			var stdout, stderr bytes.Buffer
+			env := &Env{Context: context.Background(), Stdout: &stdout, Stderr: &stderr}
+			if code := Run(env, []string{"logs", "shop", "web-0"}); code != tt.wantCode {
+				t.Errorf("exit code = %d, want %d; stderr:\n%s", code, tt.wantCode, stderr.String())
+			}
+			if !strings.Contains(stderr.String(), tt.wantStderr) {
+				t.Errorf("stderr = %q, want it to contain %q", stderr.String(), tt.wantStderr)
+			}
+		})
+	}
+}
+
+func TestFindIPWarnsAboutKindsItCouldNotList(t *testing.T) {
+	fakeKubectl(t, `case "$2" in
+pods) echo '{"items": [{"kind": "Pod", "metadata": {"name": "web-0", "namespace": "shop"}, "status": {"podIP": "10.244.1.5"}}]}' ;;
+nodes) echo 'Error from server (Forbidden): nodes is forbidden: User "jane" cannot list resource "nodes"' >&2; exit 1 ;;
+*) echo '{"items": []}' ;;
+esac`)
+	var stdout, stderr bytes.Buffer
+	env := &Env{Context: context.Background(), Stdout: &stdout, Stderr: &stderr}
+	if code := Run(env, []string{"find-ip", "10.244.1.5"}); code != exitOK {
+		t.Errorf("exit code = %d, want %d; stderr:\n%s", code, exitOK, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "web-0") {
+		t.Errorf("stdout = %q, want the pod", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "Warning: some resource kinds could not be listed: nodes: forbidden") {
+		t.Errorf("stderr = %q, want a warning about nodes", stderr.String())
+	}
+}
@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"kubetbe/msg"
+)
+
+// Output formats accepted by -o.
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatYAML  = "yaml"
+)
+
+// outputFlag adds -o and --output to fs and returns the chosen format.
+func outputFlag(fs *flag.FlagSet) *string {
+	format := formatTable
+	usage := "output format: table, json or yaml"
+	fs.StringVar(&format, "o", formatTable, usage)
+	fs.StringVar(&format, "output", formatTable, usage+" (same as -o)")
+	return &format
+}
+
+// checkFormat rejects an unknown -o value before any kubectl call is made.
+func checkFormat(format string) error {
+	switch format {
+	case formatTable, formatJSON, formatYAML:
+		return nil
+	}
+	return usagef("unknown output format %q, want table, json or yaml", format)
+}
+
+// write prints v as JSON or YAML, or calls table for the table format.
+// Field names of the JSON and YAML records are part of kubetbe's interface
+// and only ever gain fields.
+func write(w io.Writer, format string, v any, table func(io.Writer) error) error {
+	switch format {
+	case formatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case formatYAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+	return table(w)
+}
+
+// namespaceRecord is one namespace in ns output.
+type namespaceRecord struct {
+	Name      string            `json:"name" yaml:"name"`
+	Context   string            `json:"context" yaml:"context"` // empty unless listing several contexts
+	Status    string            `json:"status" yaml:"status"`
+	Created   *time.Time        `json:"created" yaml:"created"`
+	Favorite  bool              `json:"favorite" yaml:"favorite"`
+	PodsKnown bool              `json:"podsKnown" yaml:"podsKnown"` // false when pods could not be listed
+	Running   int               `json:"running" yaml:"running"`
+	Pending   int               `json:"pending" yaml:"pending"`
+	Failing   int               `json:"failing" yaml:"failing"`
+	Labels    map[string]string `json:"labels" yaml:"labels"`
+}
+
+func newNamespaceRecord(ns msg.NamespaceInfo, favorite bool) namespaceRecord {
+	r := namespaceRecord{
+		Name:      ns.Name,
+		Context:   ns.Context,
+		Status:    ns.Phase,
+		Favorite:  favorite,
+		PodsKnown: ns.PodsKnown,
+		Running:   ns.Running,
+		Pending:   ns.Pending,
+		Failing:   ns.Failing,
+		Labels:    ns.Labels,
+	}
+	if !ns.Created.IsZero() {
+		created := ns.Created.UTC()
+		r.Created = &created
+	}
+	if r.Labels == nil {
+		r.Labels = map[string]string{}
+	}
+	return r
+}
+
+// matchRecord is one resource found by find-ip.
+type matchRecord struct {
+	Kind      string `json:"kind" yaml:"kind"`
+	Namespace string `json:"namespace" yaml:"namespace"` // empty for cluster-scoped resources
+	Name      string `json:"name" yaml:"name"`
+	Field     string `json:"field" yaml:"field"`
+	Address   string `json:"address" yaml:"address"`
+	Owner     string `json:"owner" yaml:"owner"`
+	Selector  string `json:"selector" yaml:"selector"`
+}
+
+func newMatchRecord(m msg.LookupMatch) matchRecord {
+	return matchRecord{
+		Kind:      m.Kind,
+		Namespace: m.Namespace,
+		Name:      m.Name,
+		Field:     m.Field,
+		Address:   m.Address,
+		Owner:     m.Owner,
+		Selector:  m.Selector,
+	}
+}
+
+// versionRecord is the output of version.
+type versionRecord struct {
+	Version  string `json:"version" yaml:"version"`
+	Commit   string `json:"commit" yaml:"commit"`
+	Modified bool   `json:"modified" yaml:"modified"`
+	Built    string `json:"built" yaml:"built"`
+	Go       string `json:"go" yaml:"go"`
+	Platform string `json:"platform" yaml:"platform"`
+}
+
+// String renders the version as text. The first line is always
+// "kubetbe <version>", which install.sh relies on.
+func (v versionRecord) String() string {
+	s := fmt.Sprintf("kubetbe %s\n", v.Version)
+	if v.Commit != "" {
+		commit := v.Commit
+		if len(commit) > 12 {
+			commit = commit[:12]
+		}
+		if v.Modified {
+			commit += " (modified)"
+		}
+		s += fmt.Sprintf("commit:  %s\n", commit)
+	}
+	if v.Built != "" {
+		s += fmt.Sprintf("built:   %s\n", v.Built)
+	}
+	return s + fmt.Sprintf("go:      %s %s\n", v.Go, v.Platform)
+}
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Version is the release tag, set at build time with
+//
+//	go build -ldflags "-X kubetbe/cli.Version=v1.2.3"
+//
+// Builds without it fall back to the module version recorded by go install,
+// or "dev".
+var Version = ""
+
+// buildInfo describes the running binary: the release, the commit it was
+// built from when known, and the Go toolchain and platform.
+func buildInfo() versionRecord {
+	v := versionRecord{
+		Version:  Version,
+		Go:       runtime.Version(),
+		Platform: runtime.GOOS + "/" + runtime.GOARCH,
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if v.Version == "" && info.Main.Version != "" && info.Main.Version != "(devel)" {
+			v.Version = info.Main.Version
+		}
+		for _, setting := range info.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				v.Commit = setting.Value
+			case "vcs.time":
+				v.Built = setting.Value
+			case "vcs.modified":
+				v.Modified = setting.Value == "true"
+			}
+		}
+	}
+	if v.Version == "" {
+		v.Version = "dev"
+	}
+	return v
+}
+
+// VersionString describes the running binary for --version.
+func VersionString() string {
+	return buildInfo().String()
+}
@@ -0,0 +1,240 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvConfig names the environment variable that points at a config file.
+// The --config flag takes precedence over it.
+const EnvConfig = "KUBETBE_CONFIG"
+
+// Environment variables standing in for the global flags of the same name,
+// which take precedence over them.
+const (
+	EnvKubeconfig = "KUBETBE_KUBECONFIG"
+	EnvContext    = "KUBETBE_CONTEXT"
+	EnvNamespace  = "KUBETBE_NAMESPACE"
+	EnvAs         = "KUBETBE_AS"
+	EnvContexts   = "KUBETBE_CONTEXTS"
+)
+
+// Config is the user configuration read from config.yaml. Every field is
+// optional; Default fills in the values kubetbe used before it had a config
+// file.
+type Config struct {
+	// RefreshInterval is how often namespaces, pods and logs are re-fetched.
+	RefreshInterval time.Duration `yaml:"refresh_interval"`
+	// LogLoadDelay is how long a log panel waits before its first fetch,
+	// so tabbing quickly through pods doesn't start a kubectl per pod.
+	LogLoadDelay time.Duration `yaml:"log_load_delay"`
+	// NamespacePageSize caps the namespaces shown per page.
+	NamespacePageSize int `yaml:"namespace_page_size"`
+	// PodsPanelHeight is the height of the pods panel, border included.
+	PodsPanelHeight int `yaml:"pods_panel_height"`
+	// Logs are the default options for log panels.
+	Logs LogOptions `yaml:"logs"`
+	// Timeouts cap how long one kubectl call may run.
+	Timeouts TimeoutOptions `yaml:"timeouts"`
+	// Retry is how reads are retried while the cluster cannot be reached.
+	Retry RetryOptions `yaml:"retry"`
+	// StartupNamespace, if set, opens that namespace straight away.
+	StartupNamespace string `yaml:"startup_namespace"`
+	// Contexts, if set, turns on multi-cluster mode: the namespace list
+	// merges the namespaces of all these kube contexts.
+	Contexts []string `yaml:"contexts"`
+	// NamespaceLabels are the labels shown in the namespace table.
+	NamespaceLabels []string `yaml:"namespace_labels"`
+	// ProtectedNamespaces are glob patterns (path.Match syntax) for
+	// namespaces whose deletion, or the deletion of pods in them, needs the
+	// full name typed. DefaultProtectedNamespaces always apply as well.
+	ProtectedNamespaces []string `yaml:"protected_namespaces"`
+	// BulkDeleteWorkers caps how many pods a bulk delete removes at once.
+	BulkDeleteWorkers int `yaml:"bulk_delete_workers"`
+	// ReadOnly disables every action that changes cluster state.
+	ReadOnly bool `yaml:"read_only"`
+	// Theme names the color theme: dark, light, high-contrast or
+	// monochrome. NO_COLOR forces monochrome.
+	Theme string `yaml:"theme"`
+	// Keys override key bindings: action name -> keys, e.g. delete: [x].
+	// Action names are validated by the UI, which owns the keymap.
+	Keys map[string][]string `yaml:"keys"`
+
+	// Path is the file the config was loaded from, empty for defaults.
+	Path string `yaml:"-"`
+}
+
+// LogOptions map onto kubectl logs flags.
+type LogOptions struct {
+	Tail       int           `yaml:"tail"`       // --tail
+	Timestamps bool          `yaml:"timestamps"` // --timestamps
+	Since      time.Duration `yaml:"since"`      // --since, 0 for no limit
+}
+
+// TimeoutOptions are the kubectl call timeouts, 0 for no limit.
+type TimeoutOptions struct {
+	Read  time.Duration `yaml:"read"`  // listing, describe, logs
+	Write time.Duration `yaml:"write"` // delete, evict, finalizer patches
+}
+
+// RetryOptions control retries of reads that could not reach the cluster.
+type RetryOptions struct {
+	Attempts int           `yaml:"attempts"` // tries per read, 1 for no retries
+	Backoff  time.Duration `yaml:"backoff"`  // wait before the first retry, doubled after each
+}
+
+// DefaultProtectedNamespaces are the system namespaces that are always
+// protected.
+var DefaultProtectedNamespaces = []string{"default", "kube-system", "kube-public", "kube-node-lease"}
+
+// Default returns the built-in configuration.
+func Default() *Config {
+	return &Config{
+		RefreshInterval:   2 * time.Second,
+		LogLoadDelay:      3 * time.Second,
+		NamespacePageSize: 10,
+		PodsPanelHeight:   12,
+		Logs: LogOptions{
+			Tail: 50,
+		},
+		Timeouts: TimeoutOptions{
+			Read:  30 * time.Second,
+			Write: 5 * time.Minute,
+		},
+		Retry: RetryOptions{
+			Attempts: 3,
+			Backoff:  500 * time.Millisecond,
+		},
+		NamespaceLabels:   []string{"team", "owner", "env", "environment", "app.kubernetes.io/part-of"},
+		BulkDeleteWorkers: 5,
+	}
+}
+
+// Load reads the config file at path, or, when path is empty, at
+// $KUBETBE_CONFIG or the default location. A missing file at the default
+// location yields the defaults; a missing file that was asked for
+// explicitly is an error.
+func Load(path string) (*Config, error) {
+	explicit := true
+	if path == "" {
+		path = os.Getenv(EnvConfig)
+	}
+	if path == "" {
+		explicit = false
+		dir, err := Dir()
+		if err != nil {
+			return Default(), nil
+		}
+		path = filepath.Join(dir, "config.yaml")
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && !explicit {
+		return Default(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("config %s: %v", path, err)
+	}
+
+	cfg, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("config %s: %v", path, err)
+	}
+	cfg.Path = path
+	return cfg, nil
+}
+
+// Parse decodes and validates YAML config data on top of the defaults.
+// Unknown keys are rejected so typos don't go unnoticed.
+func Parse(data []byte) (*Config, error) {
+	cfg := Default()
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// dnsLabel matches a valid namespace name (RFC 1123 label).
+var dnsLabel = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// Validate checks every setting and reports all problems at once.
+func (c *Config) Validate() error {
+	var problems []string
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			problems = append(problems, fmt.Sprintf(format, args...))
+		}
+	}
+
+	check(c.RefreshInterval >= 500*time.Millisecond,
+		"refresh_interval: must be at least 500ms (got %v)", c.RefreshInterval)
+	check(c.RefreshInterval <= time.Hour,
+		"refresh_interval: must be at most 1h (got %v)", c.RefreshInterval)
+	check(c.LogLoadDelay >= 0 && c.LogLoadDelay <= time.Minute,
+		"log_load_delay: must be between 0s and 1m (got %v)", c.LogLoadDelay)
+	check(c.NamespacePageSize >= 1 && c.NamespacePageSize <= 100,
+		"namespace_page_size: must be between 1 and 100 (got %d)", c.NamespacePageSize)
+	check(c.PodsPanelHeight >= 6 && c.PodsPanelHeight <= 60,
+		"pods_panel_height: must be between 6 and 60 (got %d)", c.PodsPanelHeight)
+	check(c.Logs.Tail == -1 || (c.Logs.Tail >= 1 && c.Logs.Tail <= 10000),
+		"logs.tail: must be between 1 and 10000, or -1 for all lines (got %d)", c.Logs.Tail)
+	check(c.Logs.Since >= 0,
+		"logs.since: must not be negative (got %v)", c.Logs.Since)
+	check(c.Timeouts.Read >= 0 && c.Timeouts.Read <= time.Hour,
+		"timeouts.read: must be between 0s and 1h (got %v)", c.Timeouts.Read)
+	check(c.Timeouts.Write >= 0 && c.Timeouts.Write <= time.Hour,
+		"timeouts.write: must be between 0s and 1h (got %v)", c.Timeouts.Write)
+	check(c.Retry.Attempts >= 1 && c.Retry.Attempts <= 10,
+		"retry.attempts: must be between 1 and 10 (got %d)", c.Retry.Attempts)
+	check(c.Retry.Backoff >= 0 && c.Retry.Backoff <= 10*time.Second,
+		"retry.backoff: must be between 0s and 10s (got %v)", c.Retry.Backoff)
+	check(c.StartupNamespace == "" || (len(c.StartupNamespace) <= 63 && dnsLabel.MatchString(c.StartupNamespace)),
+		"startup_namespace: %q is not a valid namespace name", c.StartupNamespace)
+	check(c.BulkDeleteWorkers >= 1 && c.BulkDeleteWorkers <= 50,
+		"bulk_delete_workers: must be between 1 and 50 (got %d)", c.BulkDeleteWorkers)
+	seen := map[string]bool{}
+	for _, context := range c.Contexts {
+		check(strings.TrimSpace(context) != "", "contexts: entries must not be empty")
+		check(!seen[context], "contexts: %q is listed twice", context)
+		seen[context] = true
+	}
+	for _, label := range c.NamespaceLabels {
+		check(strings.TrimSpace(label) != "", "namespace_labels: entries must not be empty")
+	}
+	for _, pattern := range c.ProtectedNamespaces {
+		_, err := path.Match(pattern, "")
+		check(pattern != "" && err == nil, "protected_namespaces: %q is not a valid pattern", pattern)
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid settings:\n  - %s", strings.Join(problems, "\n  - "))
+	}
+	return nil
+}
+
+// IsProtected reports whether namespace matches a protected pattern.
+func (c *Config) IsProtected(namespace string) bool {
+	patterns := append(append([]string{}, DefaultProtectedNamespaces...), c.ProtectedNamespaces...)
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, namespace); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDefaultIsValid(t *testing.T) {
+	if err := Default().Validate(); err != nil {
+		t.Fatalf("Default().Validate() = %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(c *Config)
+		want   string // fragment of the error, "" for valid
+	}{
+		{"refresh at minimum", func(c *Config) { c.RefreshInterval = 500 * time.Millisecond }, ""},
+		{"refresh too fast", func(c *Config) { c.RefreshInterval = 100 * time.Millisecond }, "refresh_interval: must be at least 500ms"},
+		{"refresh too slow", func(c *Config) { c.RefreshInterval = 2 * time.Hour }, "refresh_interval: must be at most 1h"},
+		{"negative log delay", func(c *Config) { c.LogLoadDelay = -time.Second }, "log_load_delay"},
+		{"page size zero", func(c *Config) { c.NamespacePageSize = 0 }, "namespace_page_size: must be between 1 and 100 (got 0)"},
+		{"panel too short", func(c *Config) { c.PodsPanelHeight = 5 }, "pods_panel_height"},
+		{"all log lines", func(c *Config) { c.Logs.Tail = -1 }, ""},
+		{"tail zero", func(c *Config) { c.Logs.Tail = 0 }, "logs.tail"},
+		{"negative since", func(c *Config) { c.Logs.Since = -time.Minute }, "logs.since"},
+		{"no timeouts", func(c *Config) { c.Timeouts = TimeoutOptions{} }, ""},
+		{"read timeout too long", func(c *Config) { c.Timeouts.Read = 2 * time.Hour }, "timeouts.read"},
+		{"write timeout negative", func(c *Config) { c.Timeouts.Write = -time.Second }, "timeouts.write"},
+		{"no retries", func(c *Config) { c.Retry.Attempts = 1 }, ""},
+		{"zero attempts", func(c *Config) { c.Retry.Attempts = 0 }, "retry.attempts"},
+		{"backoff too long", func(c *Config) { c.Retry.Backoff = time.Minute }, "retry.backoff"},
+		{"startup namespace", func(c *Config) { c.StartupNamespace = "shop-v2" }, ""},
+		{"startup namespace upper case", func(c *Config) { c.StartupNamespace = "Shop" }, `startup_namespace: "Shop" is not a valid namespace name`},
+		{"startup namespace too long", func(c *Config) { c.StartupNamespace = strings.Repeat("a", 64) }, "startup_namespace"},
+		{"workers zero", func(c *Config) { c.BulkDeleteWorkers = 0 }, "bulk_delete_workers"},
+		{"contexts", func(c *Config) { c.Contexts = []string{"prod", "stage"} }, ""},
+		{"empty context", func(c *Config) { c.Contexts = []string{" "} }, "contexts: entries must not be empty"},
+		{"duplicate context", func(c *Config) { c.Contexts = []string{"prod", "prod"} }, `contexts: "prod" is listed twice`},
+		{"empty label", func(c *Config) { c.NamespaceLabels = []string{""} }, "namespace_labels"},
+		{"protected glob", func(c *Config) { c.ProtectedNamespaces = []string{"prod-*"} }, ""},
+		{"bad protected glob", func(c *Config) { c.ProtectedNamespaces = []string{"prod-["} }, `protected_namespaces: "prod-[" is not a valid pattern`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Default()
+			tt.modify(c)
+			err := c.Validate()
+			switch {
+			case tt.want == "" && err != nil:
+				t.Errorf("Validate() = %v, want no error", err)
+			case tt.want != "" && err == nil:
+				t.Errorf("Validate() = nil, want an error containing %q", tt.want)
+			case tt.want != "" && !strings.Contains(err.Error(), tt.want):
+				t.Errorf("Validate() = %v, want an error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateReportsEveryProblem(t *testing.T) {
+	c := Default()
+	c.NamespacePageSize = 0
+	c.Retry.Attempts = 0
+	err := c.Validate()
+	if err == nil {
+		t.Fatal("Validate() = nil, want an error")
+	}
+	for _, want := range []string{"namespace_page_size", "retry.attempts"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate() = %v, want it to mention %s", err, want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	cfg, err := Parse([]byte("refresh_interval: 5s\nretry:\n  attempts: 5\n"))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if cfg.RefreshInterval != 5*time.Second || cfg.Retry.Attempts != 5 {
+		t.Errorf("got refresh %v, attempts %d; want 5s, 5", cfg.RefreshInterval, cfg.Retry.Attempts)
+	}
+	// Settings left out keep their defaults
+	if cfg.Retry.Backoff != Default().Retry.Backoff {
+		t.Errorf("retry.backoff = %v, want the default %v", cfg.Retry.Backoff, Default().Retry.Backoff)
+	}
+
+	if _, err := Parse([]byte("refresh_intervall: 5s\n")); err == nil {
+		t.Error("Parse accepted an unknown key")
+	}
+	if _, err := Parse([]byte("namespace_page_size: 0\n")); err == nil {
+		t.Error("Parse accepted an invalid setting")
+	}
+	if _, err := Parse(nil); err != nil {
+		t.Errorf("Parse(empty) = %v, want the defaults", err)
+	}
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// appName is the directory name used under the XDG base directories.
+const appName = "kubetbe"
+
+// Dir returns the kubetbe configuration directory,
+// $XDG_CONFIG_HOME/kubetbe or ~/.config/kubetbe when the variable is unset.
+func Dir() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, appName), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot locate config directory: %v", err)
+	}
+	return filepath.Join(home, ".config", appName), nil
+}
+
+// StateDir returns the directory for data kubetbe accumulates, such as the
+// audit log: $XDG_STATE_HOME/kubetbe or ~/.local/state/kubetbe.
+func StateDir() (string, error) {
+	if dir := os.Getenv("XDG_STATE_HOME"); dir != "" {
+		return filepath.Join(dir, appName), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot locate state directory: %v", err)
+	}
+	return filepath.Join(home, ".local", "state", appName), nil
+}
+
+// writeFileAtomic writes data to path through a temporary file in the same
+// directory so a crash never leaves a half-written file behind.
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// State is what kubetbe remembers between sessions. It lives in
+// state.json next to the config file and is rewritten on every change.
+type State struct {
+	Favorites []string `json:"favorites"`
+
+	path string
+}
+
+// LoadState reads the state file. A missing file is not an error; it just
+// yields an empty state that will be created on the first Save.
+func LoadState() (*State, error) {
+	dir, err := Dir()
+	if err != nil {
+		return &State{}, err
+	}
+	s := &State{path: filepath.Join(dir, "state.json")}
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, fmt.Errorf("failed to read %s: %v", s.path, err)
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return s, fmt.Errorf("failed to parse %s: %v", s.path, err)
+	}
+	return s, nil
+}
+
+// Save writes the state file.
+func (s *State) Save() error {
+	if s.path == "" {
+		return fmt.Errorf("state file location unknown")
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(s.path, append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to save %s: %v", s.path, err)
+	}
+	return nil
+}
+
+// IsFavorite reports whether namespace is starred.
+func (s *State) IsFavorite(namespace string) bool {
+	for _, name := range s.Favorites {
+		if name == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// ToggleFavorite stars or unstars namespace and returns whether it is now a
+// favorite. Call Save to persist the change.
+func (s *State) ToggleFavorite(namespace string) bool {
+	for i, name := range s.Favorites {
+		if name == namespace {
+			s.Favorites = append(s.Favorites[:i], s.Favorites[i+1:]...)
+			return false
+		}
+	}
+	s.Favorites = append(s.Favorites, namespace)
+	sort.Strings(s.Favorites)
+	return true
+}
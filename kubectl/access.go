@@ -0,0 +1,131 @@
+package kubectl
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"kubetbe/msg"
+)
+
+// Permission is a verb on a resource, as kubectl auth can-i is asked about
+// it.
+type Permission struct {
+	Verb        string
+	Resource    string
+	Subresource string // e.g. eviction; empty for the resource itself
+}
+
+// The permissions kubetbe's actions in a namespace need.
+var (
+	CanDeletePods      = Permission{Verb: "delete", Resource: "pods"}
+	CanEvictPods       = Permission{Verb: "create", Resource: "pods", Subresource: "eviction"}
+	CanDeleteNamespace = Permission{Verb: "delete", Resource: "namespaces"}
+)
+
+var accessChecks = []Permission{CanDeletePods, CanEvictPods, CanDeleteNamespace}
+
+// args asks about p in namespace. A namespace is asked about as the object
+// itself, so a Role bound in it counts like it does for the real delete.
+func (p Permission) args(namespace string) []string {
+	resource := p.Resource
+	if resource == "namespaces" {
+		resource += "/" + namespace
+	}
+	args := []string{"auth", "can-i", p.Verb, resource, "-n", namespace}
+	if p.Subresource != "" {
+		args = append(args, "--subresource", p.Subresource)
+	}
+	return args
+}
+
+// Access is what kubectl auth can-i answered in a namespace. Permissions
+// that could not be checked are missing.
+type Access map[Permission]bool
+
+// Allows reports whether p is allowed or was never answered. Only a clear
+// "no" denies, so a failed check never hides an action.
+func (a Access) Allows(p Permission) bool {
+	allowed, checked := a[p]
+	return allowed || !checked
+}
+
+// accessTTL is how long answers are trusted before entering the namespace
+// again asks anew. RBAC rules rarely change while kubetbe runs.
+const accessTTL = 5 * time.Minute
+
+type accessKey struct {
+	context   string
+	namespace string
+}
+
+type accessEntry struct {
+	access  Access
+	checked time.Time
+}
+
+var (
+	accessMu    sync.Mutex
+	accessCache = map[accessKey]accessEntry{}
+)
+
+// AccessIn returns the cached answers for namespace in the named kube
+// context, "" for kubectl's current one. It is empty until CheckAccess has
+// run there.
+func AccessIn(kubeContext, namespace string) Access {
+	accessMu.Lock()
+	defer accessMu.Unlock()
+	return accessCache[accessKey{kubeContext, namespace}].access
+}
+
+// CheckAccess asks kubectl auth can-i about every permission kubetbe's
+// actions in namespace need, unless answers younger than accessTTL are
+// cached, and then reports with an AccessMsg.
+func CheckAccess(ctx context.Context, f Flags, namespace string) tea.Cmd {
+	key := accessKey{f.Context, namespace}
+	return func() tea.Msg {
+		accessMu.Lock()
+		entry, ok := accessCache[key]
+		accessMu.Unlock()
+		if ok && time.Since(entry.checked) < accessTTL {
+			return msg.AccessMsg{Context: f.Context, Namespace: namespace}
+		}
+
+		access := canI(ctx, f, namespace, accessChecks)
+		if ctx.Err() != nil {
+			return nil
+		}
+		accessMu.Lock()
+		accessCache[key] = accessEntry{access: access, checked: time.Now()}
+		accessMu.Unlock()
+		return msg.AccessMsg{Context: f.Context, Namespace: namespace}
+	}
+}
+
+// canI checks perms in namespace side by side. kubectl prints "yes", or
+// "no" with an optional reason and exits 1; anything else leaves the
+// permission unanswered.
+func canI(ctx context.Context, f Flags, namespace string, perms []Permission) Access {
+	access := Access{}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, p := range perms {
+		wg.Add(1)
+		go func(p Permission) {
+			defer wg.Done()
+			output, _, _ := runRead(ctx, f, p.args(namespace)...)
+			answer := strings.Fields(string(output))
+			if len(answer) == 0 || (answer[0] != "yes" && answer[0] != "no") {
+				return
+			}
+			mu.Lock()
+			access[p] = answer[0] == "yes"
+			mu.Unlock()
+		}(p)
+	}
+	wg.Wait()
+	return access
+}
@@ -0,0 +1,61 @@
+package kubectl
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"kubetbe/msg"
+)
+
+// DeletePods removes pods in namespace with opts, with at most workers
+// kubectl processes at once. Progress arrives on the returned channel, one
+// message when a pod's delete starts and one when it finishes; the channel
+// is closed when every pod is done. The returned command starts the work
+// and yields the first progress message; WaitBulkDelete yields the rest.
+// Pods not yet started when ctx ends are reported as cancelled.
+func DeletePods(ctx context.Context, f Flags, namespace string, pods []string, workers int, opts DeleteOptions) (<-chan msg.BulkDeleteMsg, tea.Cmd) {
+	updates := make(chan msg.BulkDeleteMsg, 2*len(pods))
+	start := func() tea.Msg {
+		jobs := make(chan string)
+		var wg sync.WaitGroup
+		for i := 0; i < workers && i < len(pods); i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for pod := range jobs {
+					updates <- msg.BulkDeleteMsg{Namespace: namespace, Pod: pod, Started: true}
+					err := deletePod(ctx, f, namespace, pod, opts)
+					if err != nil {
+						err = fmt.Errorf("failed to %s pod: %w", opts.Verb(), err)
+					}
+					updates <- msg.BulkDeleteMsg{Namespace: namespace, Pod: pod, Err: err}
+				}
+			}()
+		}
+		go func() {
+			for _, pod := range pods {
+				jobs <- pod
+			}
+			close(jobs)
+			wg.Wait()
+			close(updates)
+		}()
+		return WaitBulkDelete(namespace, updates)()
+	}
+	return updates, start
+}
+
+// WaitBulkDelete yields the next progress message of a bulk delete, or
+// msg.BulkDeleteDoneMsg once the channel is closed.
+func WaitBulkDelete(namespace string, updates <-chan msg.BulkDeleteMsg) tea.Cmd {
+	return func() tea.Msg {
+		update, ok := <-updates
+		if !ok {
+			return msg.BulkDeleteDoneMsg{Namespace: namespace}
+		}
+		return update
+	}
+}
@@ -0,0 +1,63 @@
+package kubectl
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"kubetbe/msg"
+)
+
+// GetNamespacesMulti lists the namespaces of every context at once and
+// merges them, each tagged with its context. A cluster that cannot be
+// listed is reported in its ClusterStatus and leaves the others alone.
+// Namespaces are in name order, then in the order of contexts. Each context
+// is reached with f and --context set to it.
+func GetNamespacesMulti(ctx context.Context, f Flags, contexts []string) ([]msg.NamespaceInfo, []msg.ClusterStatus) {
+	lists := make([][]msg.NamespaceInfo, len(contexts))
+	clusters := make([]msg.ClusterStatus, len(contexts))
+
+	var wg sync.WaitGroup
+	for i, name := range contexts {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			cf := f
+			cf.Context = name
+			namespaces, err := GetNamespaces(ctx, cf)
+			for j := range namespaces {
+				namespaces[j].Context = name
+			}
+			lists[i] = namespaces
+			clusters[i] = msg.ClusterStatus{Context: name, Namespaces: len(namespaces), Err: err}
+		}(i, name)
+	}
+	wg.Wait()
+
+	order := map[string]int{}
+	var merged []msg.NamespaceInfo
+	for i, list := range lists {
+		order[contexts[i]] = i
+		merged = append(merged, list...)
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		if merged[i].Name != merged[j].Name {
+			return merged[i].Name < merged[j].Name
+		}
+		return order[merged[i].Context] < order[merged[j].Context]
+	})
+	return merged, clusters
+}
+
+// FetchNamespacesMulti is FetchNamespaces for several contexts.
+func FetchNamespacesMulti(ctx context.Context, f Flags, contexts []string) tea.Cmd {
+	return func() tea.Msg {
+		namespaces, clusters := GetNamespacesMulti(ctx, f, contexts)
+		if ctx.Err() != nil {
+			return nil
+		}
+		return msg.NamespaceListMsg{Namespaces: namespaces, Clusters: clusters}
+	}
+}
@@ -2,53 +2,38 @@ package kubectl
 
 import (
 	"bufio"
+	"bytes"
+	"context"
 	"fmt"
-	"os/exec"
+	"io"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 
 	"kubetbe/msg"
 )
 
-func FetchNamespaces(searchTerm string) tea.Cmd {
+func FetchNamespaces(ctx context.Context, f Flags) tea.Cmd {
 	return func() tea.Msg {
-		// Use kubectl to get namespaces
-		cmd := exec.Command("kubectl", "get", "namespaces", "-o", "jsonpath={.items[*].metadata.name}")
-
-		output, err := cmd.Output()
-		if err != nil {
-			return msg.ErrorMsg{Err: fmt.Errorf("failed to run kubectl get namespaces command: %v", err)}
+		namespaces, err := GetNamespaces(ctx, f)
+		if ctx.Err() != nil {
+			return nil
 		}
-
-		// Parse output - jsonpath returns space-separated names
-		allNamespaces := strings.Fields(string(output))
-
-		// Filter by search term if provided
-		namespaces := []string{}
-		if searchTerm != "" {
-			searchLower := strings.ToLower(searchTerm)
-			for _, ns := range allNamespaces {
-				if strings.Contains(strings.ToLower(ns), searchLower) {
-					namespaces = append(namespaces, ns)
-				}
-			}
-		} else {
-			namespaces = allNamespaces
+		if err != nil {
+			return msg.ErrorMsg{Err: err}
 		}
-
-		return msg.NamespaceListMsg{Namespaces: namespaces}
+		return msg.NamespaceListMsg{Namespaces: namespaces, Context: f.Context}
 	}
 }
 
-func DeleteNamespace(namespace string) tea.Cmd {
+func DeleteNamespace(ctx context.Context, f Flags, namespace string) tea.Cmd {
 	return func() tea.Msg {
-		cmd := exec.Command("kubectl", "delete", "namespace", namespace)
-		err := cmd.Run()
+		_, err := mutate(ctx, f, target{Namespace: namespace, Resource: "namespace/" + namespace}, "delete", "namespace", namespace)
 		if err != nil {
 			return msg.NamespaceDeleteMsg{
 				Namespace: namespace,
-				Err:       fmt.Errorf("failed to delete namespace: %v", err),
+				Err:       fmt.Errorf("failed to delete namespace: %w", err),
 			}
 		}
 		// After successful delete, refresh the namespace list
@@ -59,15 +44,13 @@ func DeleteNamespace(namespace string) tea.Cmd {
 	}
 }
 
-func DeletePod(namespace, pod string) tea.Cmd {
+func DeletePod(ctx context.Context, f Flags, namespace, pod string, opts DeleteOptions) tea.Cmd {
 	return func() tea.Msg {
-		cmd := exec.Command("kubectl", "delete", "pod", pod, "-n", namespace)
-		err := cmd.Run()
-		if err != nil {
+		if err := deletePod(ctx, f, namespace, pod, opts); err != nil {
 			return msg.PodDeleteMsg{
 				Namespace: namespace,
 				Pod:       pod,
-				Err:       fmt.Errorf("failed to delete pod: %v", err),
+				Err:       fmt.Errorf("failed to %s pod: %w", opts.Verb(), err),
 			}
 		}
 		return msg.PodDeleteMsg{
@@ -78,10 +61,12 @@ func DeletePod(namespace, pod string) tea.Cmd {
 	}
 }
 
-func DescribePod(namespace, pod string) tea.Cmd {
+func DescribePod(ctx context.Context, f Flags, namespace, pod string) tea.Cmd {
 	return func() tea.Msg {
-		cmd := exec.Command("kubectl", "describe", "pod", pod, "-n", namespace)
-		output, err := cmd.CombinedOutput()
+		output, err := run(ctx, f, "describe", "pod", pod, "-n", namespace)
+		if ctx.Err() != nil {
+			return nil
+		}
 		if err != nil {
 			return msg.PodDescribeMsg{
 				Namespace: namespace,
@@ -109,43 +94,18 @@ func DescribePod(namespace, pod string) tea.Cmd {
 	}
 }
 
-func FindServiceByIP(ip string) tea.Cmd {
+// StartPodsWatch lists pods in namespace. A non-empty selector limits the
+// list to pods matching that label selector.
+func StartPodsWatch(ctx context.Context, f Flags, namespace, selector string) tea.Cmd {
 	return func() tea.Msg {
-		cmd := exec.Command("kubectl", "get", "services", "--all-namespaces", "-o", "wide")
-		output, err := cmd.CombinedOutput()
-		if err != nil {
-			return msg.ServiceLookupMsg{
-				IP:  ip,
-				Err: fmt.Errorf("failed to fetch services: %v", err),
-			}
-		}
-
-		lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-		var results []string
-		if len(lines) > 0 {
-			header := lines[0]
-			for _, line := range lines[1:] {
-				if strings.Contains(line, ip) {
-					if len(results) == 0 {
-						results = append(results, header)
-					}
-					results = append(results, line)
-				}
-			}
+		args := []string{"get", "pods", "-n", namespace}
+		if selector != "" {
+			args = append(args, "-l", selector)
 		}
-
-		return msg.ServiceLookupMsg{
-			IP:     ip,
-			Result: results,
-			Err:    nil,
+		output, err := run(ctx, f, args...)
+		if ctx.Err() != nil {
+			return nil
 		}
-	}
-}
-
-func StartPodsWatch(namespace string) tea.Cmd {
-	return func() tea.Msg {
-		cmd := exec.Command("kubectl", "get", "pods", "-n", namespace)
-		output, err := cmd.Output()
 		if err != nil {
 			return msg.PodUpdateMsg{Err: err}
 		}
@@ -160,13 +120,61 @@ func StartPodsWatch(namespace string) tea.Cmd {
 	}
 }
 
-func StartLogWatch(podName, namespace string) tea.Cmd {
+// LogOptions are the kubectl logs flags used by StartLogWatch and Logs.
+type LogOptions struct {
+	Tail       int // -1 for all lines
+	Timestamps bool
+	Since      time.Duration // 0 for no limit
+	Follow     bool          // keep streaming; only meaningful for Logs
+}
+
+func (o LogOptions) args() []string {
+	args := []string{fmt.Sprintf("--tail=%d", o.Tail)}
+	if o.Timestamps {
+		args = append(args, "--timestamps")
+	}
+	if o.Since > 0 {
+		args = append(args, "--since="+o.Since.String())
+	}
+	if o.Follow {
+		args = append(args, "--follow")
+	}
+	return args
+}
+
+// Logs writes the logs of pod to w as kubectl prints them. With
+// opts.Follow it returns only once the stream ends or ctx does, and the
+// read timeout does not apply.
+func Logs(ctx context.Context, f Flags, namespace, pod string, opts LogOptions, w io.Writer) error {
+	args := append([]string{"logs"}, opts.args()...)
+	args = append(args, pod, "-n", namespace)
+	var d time.Duration
+	if !opts.Follow {
+		d = currentTimeouts().Read
+	}
+	ctx, cancel := withTimeout(ctx, d)
+	defer cancel()
+	cmd := commandFlags(ctx, f, args...)
+	var stderr bytes.Buffer
+	cmd.Stdout = w
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	return failed(f, args, stderr.Bytes(), interrupted(ctx, d, err))
+}
+
+func StartLogWatch(ctx context.Context, f Flags, podName, namespace string, opts LogOptions) tea.Cmd {
 	return func() tea.Msg {
-		// Use --tail=50 to get more log lines
 		// Since we show only one panel at a time, we can show more logs
 		// renderPanel will truncate to fit the available height
-		cmd := exec.Command("kubectl", "logs", "--tail=50", podName, "-n", namespace)
-		output, err := cmd.CombinedOutput()
+		opts.Follow = false
+		var output bytes.Buffer
+		err := withRetry(ctx, f, func() error {
+			output.Reset()
+			return Logs(ctx, f, namespace, podName, opts, &output)
+		})
+		if ctx.Err() != nil {
+			return nil
+		}
 
 		if err != nil {
 			return msg.LogUpdateMsg{
@@ -177,7 +185,7 @@ func StartLogWatch(podName, namespace string) tea.Cmd {
 		}
 
 		lines := []string{}
-		scanner := bufio.NewScanner(strings.NewReader(string(output)))
+		scanner := bufio.NewScanner(&output)
 		for scanner.Scan() {
 			lines = append(lines, scanner.Text())
 		}
@@ -0,0 +1,43 @@
+package kubectl
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// fakeKubectl puts a kubectl running the shell script body first on $PATH
+// for the rest of the test.
+func fakeKubectl(t *testing.T, body string) {
+	t.Helper()
+	dir := t.TempDir()
+	script := "#!/bin/sh\n" + body + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "kubectl"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestLogsKeepsStderr(t *testing.T) {
+	const denied = `Error from server (Forbidden): pods "web-0" is forbidden: User "jane" cannot get resource "pods/log" in API group "" in the namespace "shop"`
+	fakeKubectl(t, "echo 'partial line'\necho '"+denied+"' >&2\nexit 1")
+
+	var out bytes.Buffer
+	err := Logs(context.Background(), Flags{}, "shop", "web-0", LogOptions{Tail: 10}, &out)
+	var kerr *Error
+	if !errors.As(err, &kerr) {
+		t.Fatalf("Logs() = %v, want an *Error", err)
+	}
+	if kerr.Stderr != denied {
+		t.Errorf("Stderr = %q, want %q", kerr.Stderr, denied)
+	}
+	if got := Classify(err); got != ErrForbidden {
+		t.Errorf("Classify = %v, want %v", got, ErrForbidden)
+	}
+	if out.String() != "partial line\n" {
+		t.Errorf("stdout = %q, want what kubectl printed", out.String())
+	}
+}
@@ -0,0 +1,148 @@
+package kubectl
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Retry controls how reads are retried when the cluster cannot be reached.
+// Other failures, such as a denied or malformed request, are final.
+type Retry struct {
+	Attempts int           // tries per read, 1 for no retries
+	Backoff  time.Duration // wait before the first retry, doubled after each
+}
+
+// maxBackoff caps the wait between two attempts.
+const maxBackoff = 10 * time.Second
+
+var (
+	retryMu sync.RWMutex
+	retry   = Retry{Attempts: 1}
+)
+
+// SetRetry sets the retry policy for every following read.
+func SetRetry(r Retry) {
+	retryMu.Lock()
+	defer retryMu.Unlock()
+	retry = r
+}
+
+func currentRetry() Retry {
+	retryMu.RLock()
+	defer retryMu.RUnlock()
+	return retry
+}
+
+// withRetry runs call, a read against the cluster the global flags f point
+// at, until it succeeds, fails for a reason other than an unreachable
+// cluster, runs out of attempts or ctx ends. Each outcome is recorded for
+// ConnectionTo.
+func withRetry(ctx context.Context, f Flags, call func() error) error {
+	r := currentRetry()
+	delay := r.Backoff
+	for attempt := 1; ; attempt++ {
+		start := time.Now()
+		err := call()
+		if ctx.Err() != nil {
+			return err
+		}
+		transient := Classify(err) == ErrUnreachable
+		last := err == nil || !transient || attempt >= r.Attempts
+		record(f.Context, time.Since(start), err, transient, attempt > 1, last)
+		if last {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+		delay = min(2*delay, maxBackoff)
+	}
+}
+
+// ConnState is how well a cluster is being reached.
+type ConnState int
+
+const (
+	ConnUnknown   ConnState = iota // nothing has been read yet
+	ConnConnected                  // the last read went through first time
+	ConnDegraded                   // the last read needed retries, or one read failed
+	ConnOffline                    // several reads in a row could not reach the cluster
+)
+
+func (s ConnState) String() string {
+	switch s {
+	case ConnConnected:
+		return "connected"
+	case ConnDegraded:
+		return "degraded"
+	case ConnOffline:
+		return "offline"
+	}
+	return "unknown"
+}
+
+// offlineAfter is how many reads in a row, each with all its retries, must
+// fail to reach a cluster before it counts as offline.
+const offlineAfter = 2
+
+// Connection is what the reads made so far say about reaching a cluster.
+type Connection struct {
+	State    ConnState
+	Latency  time.Duration // how long the last successful kubectl read took
+	LastOK   time.Time     // when a read last succeeded
+	Failures int           // reads in a row that could not reach the cluster
+	retried  bool          // the last successful read needed retries
+}
+
+var (
+	connectionsMu sync.Mutex
+	connections   = map[string]*Connection{}
+)
+
+// record updates the connection to kubeContext after an attempt. Failures
+// that are not about reaching the cluster say nothing about it either way.
+func record(kubeContext string, took time.Duration, err error, transient, retried, last bool) {
+	if err != nil && !transient {
+		return
+	}
+	connectionsMu.Lock()
+	defer connectionsMu.Unlock()
+	c := connections[kubeContext]
+	if c == nil {
+		c = &Connection{}
+		connections[kubeContext] = c
+	}
+	switch {
+	case err == nil:
+		c.Latency = took
+		c.LastOK = time.Now()
+		c.Failures = 0
+		c.retried = retried
+	case last:
+		c.Failures++
+	default:
+		return // retrying; only the final attempt counts
+	}
+	switch {
+	case c.Failures >= offlineAfter:
+		c.State = ConnOffline
+	case c.Failures > 0 || c.retried:
+		c.State = ConnDegraded
+	default:
+		c.State = ConnConnected
+	}
+}
+
+// ConnectionTo reports on the cluster of the named kube context, "" for
+// kubectl's current context.
+func ConnectionTo(kubeContext string) Connection {
+	connectionsMu.Lock()
+	defer connectionsMu.Unlock()
+	if c := connections[kubeContext]; c != nil {
+		return *c
+	}
+	return Connection{}
+}
@@ -0,0 +1,126 @@
+package kubectl
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+var (
+	errRefused   = kubectlFailed("The connection to the server 127.0.0.1:6443 was refused - did you specify the right host or port?")
+	errForbidden = kubectlFailed(`Error from server (Forbidden): pods is forbidden: User "jane" cannot list resource "pods"`)
+)
+
+// useRetry sets r for the rest of the test and restores the old policy
+// and forgets every recorded connection after it.
+func useRetry(t *testing.T, r Retry) {
+	old := currentRetry()
+	SetRetry(r)
+	t.Cleanup(func() {
+		SetRetry(old)
+		connectionsMu.Lock()
+		connections = map[string]*Connection{}
+		connectionsMu.Unlock()
+	})
+}
+
+// failing returns a call that fails with the given errors in turn, then
+// succeeds, and counts how often it ran.
+func failing(calls *int, errs ...error) func() error {
+	return func() error {
+		*calls++
+		if *calls <= len(errs) {
+			return errs[*calls-1]
+		}
+		return nil
+	}
+}
+
+func TestWithRetry(t *testing.T) {
+	tests := []struct {
+		name      string
+		attempts  int
+		errs      []error
+		wantCalls int
+		wantErr   error
+		wantState ConnState
+	}{
+		{"first try", 3, nil, 1, nil, ConnConnected},
+		{"recovers after unreachable", 3, []error{errRefused, errRefused}, 3, nil, ConnDegraded},
+		{"gives up after attempts", 3, []error{errRefused, errRefused, errRefused}, 3, errRefused, ConnDegraded},
+		{"no retries", 1, []error{errRefused}, 1, errRefused, ConnDegraded},
+		{"forbidden is final", 3, []error{errForbidden}, 1, errForbidden, ConnUnknown},
+		{"forbidden after unreachable", 3, []error{errRefused, errForbidden}, 2, errForbidden, ConnUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useRetry(t, Retry{Attempts: tt.attempts, Backoff: time.Millisecond})
+			f := Flags{Context: "test-" + tt.name}
+			calls := 0
+			err := withRetry(context.Background(), f, failing(&calls, tt.errs...))
+			if calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", calls, tt.wantCalls)
+			}
+			if !errors.Is(err, tt.wantErr) && err != tt.wantErr {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+			if got := ConnectionTo(f.Context).State; got != tt.wantState {
+				t.Errorf("state = %v, want %v", got, tt.wantState)
+			}
+		})
+	}
+}
+
+func TestWithRetryGoesOfflineAfterRepeatedFailures(t *testing.T) {
+	useRetry(t, Retry{Attempts: 2, Backoff: time.Millisecond})
+	f := Flags{Context: "test-offline"}
+	for i := 0; i < offlineAfter; i++ {
+		calls := 0
+		withRetry(context.Background(), f, failing(&calls, errRefused, errRefused))
+	}
+	if got := ConnectionTo(f.Context); got.State != ConnOffline || got.Failures != offlineAfter {
+		t.Errorf("connection = %+v, want offline after %d failures", got, offlineAfter)
+	}
+
+	calls := 0
+	if err := withRetry(context.Background(), f, failing(&calls)); err != nil {
+		t.Fatalf("err = %v", err)
+	}
+	if got := ConnectionTo(f.Context); got.State != ConnConnected || got.Failures != 0 {
+		t.Errorf("connection = %+v, want connected again", got)
+	}
+}
+
+func TestWithRetryStopsWhenContextEnds(t *testing.T) {
+	useRetry(t, Retry{Attempts: 5, Backoff: time.Hour})
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	done := make(chan error)
+	go func() {
+		done <- withRetry(ctx, Flags{Context: "test-cancel"}, failing(&calls, errRefused, errRefused))
+	}()
+	cancel()
+	select {
+	case err := <-done:
+		if err != errRefused {
+			t.Errorf("err = %v, want %v", err, errRefused)
+		}
+		if calls != 1 {
+			t.Errorf("calls = %d, want 1", calls)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("withRetry kept waiting after its context ended")
+	}
+}
+
+func TestWithRetryBacksOff(t *testing.T) {
+	useRetry(t, Retry{Attempts: 3, Backoff: 20 * time.Millisecond})
+	calls := 0
+	start := time.Now()
+	withRetry(context.Background(), Flags{Context: "test-backoff"}, failing(&calls, errRefused, errRefused))
+	// 20ms before the second attempt, doubled to 40ms before the third
+	if took := time.Since(start); took < 60*time.Millisecond {
+		t.Errorf("three attempts took %v, want at least 60ms of backoff", took)
+	}
+}
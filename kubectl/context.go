@@ -0,0 +1,151 @@
+package kubectl
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"kubetbe/msg"
+)
+
+// Flags are kubectl's global flags that kubetbe passes to every call.
+// Empty fields are left out, so kubectl falls back to its own defaults:
+// $KUBECONFIG, the kubeconfig's current context, no impersonation.
+//
+// Every call takes its Flags as an argument, taken by the caller when the
+// call is set up. A command queued before a context switch, or a bulk
+// delete still working through its pods, keeps reaching the cluster it was
+// started for.
+type Flags struct {
+	Kubeconfig string // --kubeconfig
+	Context    string // --context
+	As         string // --as, the user to impersonate
+}
+
+// args renders the flags that are set.
+func (f Flags) args() []string {
+	var args []string
+	if f.Kubeconfig != "" {
+		args = append(args, "--kubeconfig", f.Kubeconfig)
+	}
+	if f.Context != "" {
+		args = append(args, "--context", f.Context)
+	}
+	if f.As != "" {
+		args = append(args, "--as", f.As)
+	}
+	return args
+}
+
+var (
+	flagsMu sync.RWMutex
+	flags   Flags
+)
+
+// SetFlags sets the flags CurrentFlags returns.
+func SetFlags(f Flags) {
+	flagsMu.Lock()
+	defer flagsMu.Unlock()
+	flags = f
+}
+
+// Context returns the kube context passed with --context, or "" when
+// kubectl's current context is used.
+func Context() string {
+	flagsMu.RLock()
+	defer flagsMu.RUnlock()
+	return flags.Context
+}
+
+// CurrentFlags returns the flags set with SetFlags, for the next call to
+// be set up. Callers change Context to reach another cluster; the
+// kubeconfig itself is never changed, so other terminals keep their context.
+func CurrentFlags() Flags {
+	flagsMu.RLock()
+	defer flagsMu.RUnlock()
+	return flags
+}
+
+// waitDelay is how long a killed kubectl may hold its output open, e.g.
+// through a credential plugin it started, before kubetbe stops waiting.
+const waitDelay = 2 * time.Second
+
+// commandFlags prepares a kubectl invocation with the global flags f.
+// Every kubectl call goes through here so none of them can miss a flag.
+// kubectl is killed when ctx ends.
+func commandFlags(ctx context.Context, f Flags, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "kubectl", append(f.args(), args...)...)
+	cmd.WaitDelay = waitDelay
+	return cmd
+}
+
+// FetchContexts lists the contexts in the kubeconfig.
+func FetchContexts(ctx context.Context, f Flags) tea.Cmd {
+	return func() tea.Msg {
+		output, err := run(ctx, f, "config", "get-contexts")
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return msg.ContextListMsg{Err: fmt.Errorf("failed to list contexts: %w", err)}
+		}
+		contexts, current := parseContexts(string(output))
+		return msg.ContextListMsg{Contexts: contexts, Current: current}
+	}
+}
+
+// parseContexts reads the table printed by kubectl config get-contexts.
+// Columns are found by their header offsets because CLUSTER, AUTHINFO and
+// NAMESPACE may be blank. It also returns the kubeconfig's current context,
+// marked with "*".
+func parseContexts(output string) ([]msg.KubeContext, string) {
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	if !scanner.Scan() {
+		return nil, ""
+	}
+	header := scanner.Text()
+	columns := []string{"NAME", "CLUSTER", "AUTHINFO", "NAMESPACE"}
+	offsets := make([]int, len(columns))
+	for i, name := range columns {
+		offsets[i] = strings.Index(header, name)
+		if offsets[i] < 0 {
+			return nil, ""
+		}
+	}
+	field := func(line string, i int) string {
+		if offsets[i] >= len(line) {
+			return ""
+		}
+		end := len(line)
+		if i+1 < len(offsets) && offsets[i+1] < end {
+			end = offsets[i+1]
+		}
+		return strings.TrimSpace(line[offsets[i]:end])
+	}
+
+	var contexts []msg.KubeContext
+	current := ""
+	for scanner.Scan() {
+		line := scanner.Text()
+		c := msg.KubeContext{
+			Name:      field(line, 0),
+			Cluster:   field(line, 1),
+			User:      field(line, 2),
+			Namespace: field(line, 3),
+		}
+		if c.Name == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "*") {
+			current = c.Name
+		}
+		contexts = append(contexts, c)
+	}
+	return contexts, current
+}
@@ -0,0 +1,139 @@
+package kubectl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"kubetbe/msg"
+)
+
+// DiagnoseNamespace gathers what keeps a namespace from going away: its
+// conditions and finalizers, and every resource still in it with the
+// finalizers each one is waiting on.
+func DiagnoseNamespace(ctx context.Context, f Flags, namespace string) tea.Cmd {
+	return func() tea.Msg {
+		d := msg.NamespaceDiagnosisMsg{Namespace: namespace}
+
+		output, err := run(ctx, f, "get", "namespace", namespace, "-o", "json")
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			d.Err = fmt.Errorf("failed to get namespace: %w", err)
+			return d
+		}
+		var ns struct {
+			Metadata struct {
+				DeletionTimestamp *time.Time `json:"deletionTimestamp"`
+			} `json:"metadata"`
+			Spec struct {
+				Finalizers []string `json:"finalizers"`
+			} `json:"spec"`
+			Status struct {
+				Phase      string `json:"phase"`
+				Conditions []struct {
+					Type    string `json:"type"`
+					Status  string `json:"status"`
+					Reason  string `json:"reason"`
+					Message string `json:"message"`
+				} `json:"conditions"`
+			} `json:"status"`
+		}
+		if err := json.Unmarshal(output, &ns); err != nil {
+			d.Err = fmt.Errorf("failed to parse namespace: %w", err)
+			return d
+		}
+		d.Phase = ns.Status.Phase
+		if ns.Metadata.DeletionTimestamp != nil {
+			d.DeletionTime = *ns.Metadata.DeletionTimestamp
+		}
+		d.Finalizers = ns.Spec.Finalizers
+		for _, c := range ns.Status.Conditions {
+			d.Conditions = append(d.Conditions, msg.NamespaceCondition{
+				Type: c.Type, Status: c.Status, Reason: c.Reason, Message: c.Message,
+			})
+		}
+
+		d.Resources, d.ListErr = remainingResources(ctx, f, namespace)
+		if ctx.Err() != nil {
+			return nil
+		}
+		return d
+	}
+}
+
+// remainingResources lists every resource left in namespace, sorted by API
+// group and then by reference.
+func remainingResources(ctx context.Context, f Flags, namespace string) ([]msg.RemainingResource, error) {
+	output, listErr := getNamespaced(ctx, f, namespace, "json")
+	if output == nil {
+		return nil, listErr
+	}
+
+	var list struct {
+		Items []struct {
+			APIVersion string `json:"apiVersion"`
+			Kind       string `json:"kind"`
+			Metadata   struct {
+				Name              string     `json:"name"`
+				Finalizers        []string   `json:"finalizers"`
+				DeletionTimestamp *time.Time `json:"deletionTimestamp"`
+			} `json:"metadata"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(output, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse remaining resources: %w", err)
+	}
+
+	resources := make([]msg.RemainingResource, 0, len(list.Items))
+	for _, item := range list.Items {
+		group := ""
+		if i := strings.LastIndex(item.APIVersion, "/"); i >= 0 {
+			group = item.APIVersion[:i]
+		}
+		// kind.group/name, the form kubectl get -o name prints
+		ref := strings.ToLower(item.Kind)
+		if group != "" {
+			ref += "." + group
+		}
+		ref += "/" + item.Metadata.Name
+
+		if group == "" {
+			group = "core"
+		}
+		resources = append(resources, msg.RemainingResource{
+			Group:      group,
+			Ref:        ref,
+			Finalizers: item.Metadata.Finalizers,
+			Deleting:   item.Metadata.DeletionTimestamp != nil,
+		})
+	}
+	sort.Slice(resources, func(i, j int) bool {
+		if resources[i].Group != resources[j].Group {
+			return resources[i].Group < resources[j].Group
+		}
+		return resources[i].Ref < resources[j].Ref
+	})
+	return resources, listErr
+}
+
+// ClearFinalizers removes every finalizer from ref (kind.group/name) in
+// namespace, letting the API server finish deleting it. This skips whatever
+// cleanup the finalizers stood for, so the UI guards it with a typed
+// confirmation.
+func ClearFinalizers(ctx context.Context, f Flags, namespace, ref string) tea.Cmd {
+	return func() tea.Msg {
+		_, err := mutate(ctx, f, target{Namespace: namespace, Resource: ref},
+			"patch", ref, "-n", namespace, "--type=merge", "-p", `{"metadata":{"finalizers":null}}`)
+		if err != nil {
+			err = fmt.Errorf("failed to clear finalizers on %s: %w", ref, err)
+		}
+		return msg.FinalizersClearedMsg{Namespace: namespace, Ref: ref, Err: err}
+	}
+}
@@ -0,0 +1,188 @@
+package kubectl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"kubetbe/utils"
+)
+
+// Error is a kubectl call that failed, with everything kubectl printed on
+// stderr about it.
+type Error struct {
+	Command string // the command line that was run
+	Stderr  string
+	Err     error // how kubectl ended: exit status, not started, killed
+}
+
+func (e *Error) Error() string {
+	if e.Stderr != "" {
+		return fmt.Sprintf("%v: %s", e.Err, e.Stderr)
+	}
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// failed wraps the error of a kubectl call run with the global flags f and
+// args in an *Error, keeping its stderr.
+func failed(f Flags, args []string, stderr []byte, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{
+		Command: commandLine(append(f.args(), args...)),
+		Stderr:  strings.TrimSpace(string(stderr)),
+		Err:     err,
+	}
+}
+
+// ErrorKind is the broad cause of a failed kubectl call.
+type ErrorKind int
+
+const (
+	ErrUnknown     ErrorKind = iota
+	ErrNoKubectl             // kubectl is not installed or not on $PATH
+	ErrTimeout               // the call ran past its timeout
+	ErrAuthExpired           // credentials are missing, expired or rejected
+	ErrForbidden             // RBAC denied the request
+	ErrNotFound              // the resource, namespace or context does not exist
+	ErrUnreachable           // the API server could not be reached
+)
+
+// Markers are fragments of kubectl and API server messages, matched in
+// lower case, that identify a kind of failure. Kinds are tried in the
+// order of classifiers, so a message naming an expired token inside a
+// connection error counts as an auth problem.
+var (
+	authMarkers = []string{
+		"unauthorized",
+		"you must be logged in",
+		"provide credentials",
+		"token has expired",
+		"token is expired",
+		"invalid_grant",
+		"getting credentials",
+		"refresh token",
+	}
+	forbiddenMarkers = []string{
+		"forbidden",
+		"cannot list resource",
+		"cannot get resource",
+		"cannot delete resource",
+		"cannot patch resource",
+		"cannot create resource",
+	}
+	unreachableMarkers = []string{
+		"unable to connect to the server",
+		"the connection to the server",
+		"connection refused",
+		"no such host",
+		"i/o timeout",
+		"tls handshake timeout",
+		"network is unreachable",
+		"no route to host",
+		"the server is currently unable to handle the request",
+		"x509:",
+	}
+	notFoundMarkers = []string{
+		"notfound",
+		"not found",
+		"doesn't have a resource type",
+		"does not exist",
+		"no context exists",
+	}
+)
+
+var classifiers = []struct {
+	kind    ErrorKind
+	markers []string
+}{
+	{ErrAuthExpired, authMarkers},
+	{ErrForbidden, forbiddenMarkers},
+	{ErrUnreachable, unreachableMarkers},
+	{ErrNotFound, notFoundMarkers},
+}
+
+// Classify works out why a kubectl call failed from the error and what
+// kubectl printed.
+func Classify(err error) ErrorKind {
+	if err == nil {
+		return ErrUnknown
+	}
+	if errors.Is(err, exec.ErrNotFound) {
+		return ErrNoKubectl
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrTimeout
+	}
+	s := strings.ToLower(err.Error())
+	if strings.Contains(s, `"kubectl": executable file not found`) {
+		return ErrNoKubectl
+	}
+	for _, c := range classifiers {
+		for _, marker := range c.markers {
+			if strings.Contains(s, marker) {
+				return c.kind
+			}
+		}
+	}
+	return ErrUnknown
+}
+
+// IsAuthError reports whether err came from kubectl being refused by the
+// cluster: missing or expired credentials, or RBAC denying the request.
+func IsAuthError(err error) bool {
+	kind := Classify(err)
+	return kind == ErrAuthExpired || kind == ErrForbidden
+}
+
+// Explanation describes a failed kubectl call for people.
+type Explanation struct {
+	Kind    ErrorKind
+	Summary string // what went wrong, in plain words
+	Fix     string // what to try, empty if there is no general advice
+	Command string // the kubectl command line, if known
+	Raw     string // what kubectl printed, or the error itself
+}
+
+// Explain classifies err and says what it means and how to fix it. An
+// unknown failure is summarized by the error itself.
+func Explain(err error) Explanation {
+	e := Explanation{Kind: Classify(err), Raw: err.Error()}
+	var kerr *Error
+	if errors.As(err, &kerr) {
+		e.Command = kerr.Command
+		if kerr.Stderr != "" {
+			e.Raw = kerr.Stderr
+		}
+	}
+	switch e.Kind {
+	case ErrNoKubectl:
+		e.Summary = "kubectl is not installed or not on your PATH."
+		e.Fix = "Install kubectl (https://kubernetes.io/docs/tasks/tools/) and make sure `kubectl version --client` works in this shell."
+	case ErrTimeout:
+		e.Summary = "kubectl took too long and was stopped."
+		e.Fix = "The cluster may be slow or unreachable. Retry, or raise timeouts.read / timeouts.write in the config file."
+	case ErrAuthExpired:
+		e.Summary = "Your credentials for this cluster are missing or have expired."
+		e.Fix = "Log in again with your provider (e.g. gcloud auth login, aws sso login, az login, kubelogin) and retry."
+	case ErrForbidden:
+		e.Summary = "You are not allowed to do this: RBAC denied the request."
+		e.Fix = "Ask a cluster admin for the permission, or check `kubectl auth can-i`. --as may be impersonating the wrong user."
+	case ErrNotFound:
+		e.Summary = "The resource, namespace or context does not exist (any more)."
+		e.Fix = "Refresh the list; it may have been deleted. Check the context and namespace names."
+	case ErrUnreachable:
+		e.Summary = "The cluster's API server cannot be reached."
+		e.Fix = "Check your network, VPN or proxy, and that the kube context points at a running cluster."
+	default:
+		e.Summary = utils.FirstLine(err.Error())
+	}
+	return e
+}
@@ -0,0 +1,124 @@
+package kubectl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"testing"
+)
+
+// kubectlFailed is a failed call as run returns it: exit status 1 with
+// stderr attached.
+func kubectlFailed(stderr string) error {
+	return failed(Flags{}, []string{"get", "pods"}, []byte(stderr+"\n"), errors.New("exit status 1"))
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want ErrorKind
+	}{
+		{"nil", nil, ErrUnknown},
+		{
+			"connection refused",
+			kubectlFailed("The connection to the server 127.0.0.1:6443 was refused - did you specify the right host or port?"),
+			ErrUnreachable,
+		},
+		{
+			"dial refused",
+			kubectlFailed(`Unable to connect to the server: dial tcp 10.0.0.1:443: connect: connection refused`),
+			ErrUnreachable,
+		},
+		{
+			"no such host",
+			kubectlFailed(`Unable to connect to the server: dial tcp: lookup api.example.internal on 127.0.0.53:53: no such host`),
+			ErrUnreachable,
+		},
+		{
+			"dial timeout",
+			kubectlFailed(`Unable to connect to the server: dial tcp 10.0.0.1:443: i/o timeout`),
+			ErrUnreachable,
+		},
+		{
+			"server unavailable",
+			kubectlFailed(`Error from server (ServiceUnavailable): the server is currently unable to handle the request`),
+			ErrUnreachable,
+		},
+		{
+			"forbidden list",
+			kubectlFailed(`Error from server (Forbidden): pods is forbidden: User "jane" cannot list resource "pods" in API group "" in the namespace "shop"`),
+			ErrForbidden,
+		},
+		{
+			"forbidden delete",
+			kubectlFailed(`Error from server (Forbidden): namespaces "shop" is forbidden: User "system:serviceaccount:ci:deployer" cannot delete resource "namespaces" in API group "" in the namespace "shop"`),
+			ErrForbidden,
+		},
+		{
+			"unauthorized",
+			kubectlFailed(`error: You must be logged in to the server (Unauthorized)`),
+			ErrAuthExpired,
+		},
+		{
+			"expired token behind a connection error",
+			kubectlFailed(`Unable to connect to the server: getting credentials: exec: executable gke-gcloud-auth-plugin failed with exit code 1: token has expired`),
+			ErrAuthExpired,
+		},
+		{
+			"pod not found",
+			kubectlFailed(`Error from server (NotFound): pods "web-7d9f8b6c5-x2x9k" not found`),
+			ErrNotFound,
+		},
+		{
+			"namespace not found",
+			kubectlFailed(`Error from server (NotFound): namespaces "gone" not found`),
+			ErrNotFound,
+		},
+		{
+			"unknown resource type",
+			kubectlFailed(`error: the server doesn't have a resource type "widgets"`),
+			ErrNotFound,
+		},
+		{
+			"missing context",
+			kubectlFailed(`error: context "prod" does not exist`),
+			ErrNotFound,
+		},
+		{"deadline", fmt.Errorf("kubectl get pods: %w", context.DeadlineExceeded), ErrTimeout},
+		{"no kubectl", &exec.Error{Name: "kubectl", Err: exec.ErrNotFound}, ErrNoKubectl},
+		{
+			"no kubectl as text",
+			errors.New(`exec: "kubectl": executable file not found in $PATH`),
+			ErrNoKubectl,
+		},
+		{
+			"unrecognised",
+			kubectlFailed(`error: unknown flag: --bogus`),
+			ErrUnknown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Classify(tt.err); got != tt.want {
+				t.Errorf("Classify(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExplainKeepsStderrAndCommand(t *testing.T) {
+	err := failed(Flags{Context: "stage"}, []string{"get", "pods", "-n", "shop"},
+		[]byte("Error from server (Forbidden): pods is forbidden\n"), errors.New("exit status 1"))
+	e := Explain(err)
+	if e.Kind != ErrForbidden {
+		t.Errorf("Kind = %v, want %v", e.Kind, ErrForbidden)
+	}
+	if want := "Error from server (Forbidden): pods is forbidden"; e.Raw != want {
+		t.Errorf("Raw = %q, want %q", e.Raw, want)
+	}
+	if want := "kubectl --context stage get pods -n shop"; e.Command != want {
+		t.Errorf("Command = %q, want %q", e.Command, want)
+	}
+}
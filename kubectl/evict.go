@@ -0,0 +1,212 @@
+package kubectl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"kubetbe/msg"
+)
+
+// DeleteOptions control how a pod is removed. The zero value is a plain
+// kubectl delete pod.
+type DeleteOptions struct {
+	GracePeriod int  // seconds; 0 keeps the pod's own grace period
+	Force       bool // remove immediately without waiting for the kubelet; delete only
+	Evict       bool // use the Eviction API, which honors PodDisruptionBudgets
+}
+
+// Verb is "evict" or "delete".
+func (o DeleteOptions) Verb() string {
+	if o.Evict {
+		return "evict"
+	}
+	return "delete"
+}
+
+// String describes the options for prompts, e.g. "evict, grace period 10s".
+func (o DeleteOptions) String() string {
+	s := o.Verb()
+	if o.Force {
+		s += ", force"
+	} else if o.GracePeriod > 0 {
+		s += fmt.Sprintf(", grace period %ds", o.GracePeriod)
+	}
+	return s
+}
+
+// deletePod removes pod with opts, through mutate so read-only mode and the
+// audit log apply.
+func deletePod(ctx context.Context, f Flags, namespace, pod string, opts DeleteOptions) error {
+	t := target{Namespace: namespace, Resource: "pod/" + pod}
+	if opts.Evict {
+		return evictPod(ctx, f, t, namespace, pod, opts)
+	}
+
+	args := []string{"delete", "pod", pod, "-n", namespace}
+	switch {
+	case opts.Force:
+		args = append(args, "--grace-period=0", "--force")
+	case opts.GracePeriod > 0:
+		args = append(args, "--grace-period="+strconv.Itoa(opts.GracePeriod))
+	}
+	_, err := mutate(ctx, f, t, args...)
+	return err
+}
+
+// evictPod posts an Eviction for pod. The API server refuses it with "Too
+// Many Requests" when a PodDisruptionBudget would be violated. Force does
+// not apply: an eviction always goes through the budget.
+func evictPod(ctx context.Context, f Flags, t target, namespace, pod string, opts DeleteOptions) error {
+	eviction := map[string]interface{}{
+		"apiVersion": "policy/v1",
+		"kind":       "Eviction",
+		"metadata":   map[string]string{"name": pod, "namespace": namespace},
+	}
+	if opts.GracePeriod > 0 {
+		eviction["deleteOptions"] = map[string]int{"gracePeriodSeconds": opts.GracePeriod}
+	}
+	body, err := json.Marshal(eviction)
+	if err != nil {
+		return err
+	}
+	path := fmt.Sprintf("/api/v1/namespaces/%s/pods/%s/eviction", namespace, pod)
+	_, err = mutateInput(ctx, f, t, body, "create", "--raw", path, "-f", "-")
+	return err
+}
+
+// labelSelector is a metav1.LabelSelector.
+type labelSelector struct {
+	MatchLabels      map[string]string `json:"matchLabels"`
+	MatchExpressions []struct {
+		Key      string   `json:"key"`
+		Operator string   `json:"operator"`
+		Values   []string `json:"values"`
+	} `json:"matchExpressions"`
+}
+
+// matches reports whether labels satisfy the selector. An empty selector
+// matches everything.
+func (s labelSelector) matches(labels map[string]string) bool {
+	for k, v := range s.MatchLabels {
+		if labels[k] != v {
+			return false
+		}
+	}
+	for _, e := range s.MatchExpressions {
+		value, has := labels[e.Key]
+		in := false
+		for _, v := range e.Values {
+			if v == value {
+				in = true
+			}
+		}
+		switch e.Operator {
+		case "In":
+			if !has || !in {
+				return false
+			}
+		case "NotIn":
+			if has && in {
+				return false
+			}
+		case "Exists":
+			if !has {
+				return false
+			}
+		case "DoesNotExist":
+			if has {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// CheckDisruption works out which PodDisruptionBudgets in namespace would
+// be violated by removing pods, for the warning in the delete dialog. Pods
+// that have already finished, such as Evicted or Completed ones, are not
+// counted: removing them takes nothing from a budget.
+func CheckDisruption(ctx context.Context, f Flags, namespace string, pods []string) tea.Cmd {
+	return func() tea.Msg {
+		result := msg.DisruptionCheckMsg{Namespace: namespace, Pods: pods}
+
+		output, err := run(ctx, f, "get", "poddisruptionbudgets", "-n", namespace, "-o", "json")
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			result.Err = fmt.Errorf("failed to list PodDisruptionBudgets: %w", err)
+			return result
+		}
+		var pdbs struct {
+			Items []struct {
+				Metadata objectMeta `json:"metadata"`
+				Spec     struct {
+					Selector *labelSelector `json:"selector"`
+				} `json:"spec"`
+				Status struct {
+					DisruptionsAllowed int `json:"disruptionsAllowed"`
+				} `json:"status"`
+			} `json:"items"`
+		}
+		if err := json.Unmarshal(output, &pdbs); err != nil {
+			result.Err = fmt.Errorf("failed to parse PodDisruptionBudgets: %w", err)
+			return result
+		}
+		if len(pdbs.Items) == 0 {
+			return result
+		}
+
+		output, err = run(ctx, f, "get", "pods", "-n", namespace, "-o", "json")
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			result.Err = fmt.Errorf("failed to list pods: %w", err)
+			return result
+		}
+		var list objectList
+		if err := json.Unmarshal(output, &list); err != nil {
+			result.Err = fmt.Errorf("failed to parse pods: %w", err)
+			return result
+		}
+		targets := map[string]bool{}
+		for _, pod := range pods {
+			targets[pod] = true
+		}
+
+		for _, pdb := range pdbs.Items {
+			if pdb.Spec.Selector == nil {
+				continue
+			}
+			var covered []string
+			for _, pod := range list.Items {
+				if !targets[pod.Metadata.Name] || finished(pod.Status.Phase) {
+					continue
+				}
+				if pdb.Spec.Selector.matches(pod.Metadata.Labels) {
+					covered = append(covered, pod.Metadata.Name)
+				}
+			}
+			if len(covered) > pdb.Status.DisruptionsAllowed {
+				sort.Strings(covered)
+				result.Violations = append(result.Violations, msg.DisruptionViolation{
+					Budget:  pdb.Metadata.Name,
+					Allowed: pdb.Status.DisruptionsAllowed,
+					Pods:    covered,
+				})
+			}
+		}
+		return result
+	}
+}
+
+// finished reports whether a pod in phase has stopped for good.
+func finished(phase string) bool {
+	return phase == "Succeeded" || phase == "Failed"
+}
@@ -0,0 +1,55 @@
+package kubectl
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"kubetbe/msg"
+)
+
+// budgetCluster has one budget over app=web that allows one disruption,
+// two running web pods and two web pods that have already finished.
+const budgetCluster = `case "$2" in
+poddisruptionbudgets) echo '{"items": [{"metadata": {"name": "web"}, "spec": {"selector": {"matchLabels": {"app": "web"}}}, "status": {"disruptionsAllowed": 1}}]}' ;;
+pods) echo '{"items": [
+	{"metadata": {"name": "web-0", "labels": {"app": "web"}}, "status": {"phase": "Running"}},
+	{"metadata": {"name": "web-1", "labels": {"app": "web"}}, "status": {"phase": "Running"}},
+	{"metadata": {"name": "web-evicted", "labels": {"app": "web"}}, "status": {"phase": "Failed"}},
+	{"metadata": {"name": "web-migrate", "labels": {"app": "web"}}, "status": {"phase": "Succeeded"}},
+	{"metadata": {"name": "db-0", "labels": {"app": "db"}}, "status": {"phase": "Running"}}
+]}' ;;
+esac`
+
+func TestCheckDisruption(t *testing.T) {
+	fakeKubectl(t, budgetCluster)
+	tests := []struct {
+		name string
+		pods []string
+		want []msg.DisruptionViolation
+	}{
+		{"one running pod fits the budget", []string{"web-0"}, nil},
+		{
+			"two running pods do not",
+			[]string{"web-0", "web-1"},
+			[]msg.DisruptionViolation{{Budget: "web", Allowed: 1, Pods: []string{"web-0", "web-1"}}},
+		},
+		{"finished pods take nothing from the budget", []string{"web-evicted", "web-migrate"}, nil},
+		{"finished pods next to a running one", []string{"web-0", "web-evicted", "web-migrate"}, nil},
+		{"pods outside the budget", []string{"db-0", "web-0"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := CheckDisruption(context.Background(), Flags{}, "shop", tt.pods)().(msg.DisruptionCheckMsg)
+			if !ok {
+				t.Fatal("CheckDisruption did not report a DisruptionCheckMsg")
+			}
+			if got.Err != nil {
+				t.Fatalf("Err = %v", got.Err)
+			}
+			if !reflect.DeepEqual(got.Violations, tt.want) {
+				t.Errorf("Violations = %+v, want %+v", got.Violations, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package kubectl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Timeouts cap how long a single kubectl call may run before it is killed.
+// Zero means no limit.
+type Timeouts struct {
+	Read  time.Duration // get, describe, logs and every other read
+	Write time.Duration // delete, evict and other calls through mutate
+}
+
+var (
+	timeoutsMu sync.RWMutex
+	timeouts   Timeouts
+)
+
+// SetTimeouts sets the limits for every following kubectl call.
+func SetTimeouts(t Timeouts) {
+	timeoutsMu.Lock()
+	defer timeoutsMu.Unlock()
+	timeouts = t
+}
+
+// currentTimeouts returns the limits set for kubectl calls.
+func currentTimeouts() Timeouts {
+	timeoutsMu.RLock()
+	defer timeoutsMu.RUnlock()
+	return timeouts
+}
+
+// withTimeout bounds ctx by d, unless d is zero.
+func withTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if d <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, d)
+}
+
+// interrupted explains the failure of a call whose context ended: kubectl
+// was killed, and "signal: killed" alone does not say why. The tea.Cmd
+// helpers go further for a cancelled caller: they return no message at all,
+// since nobody is waiting for the result any more.
+func interrupted(ctx context.Context, d time.Duration, err error) error {
+	if err == nil || ctx.Err() == nil {
+		return err
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("kubectl timed out after %s: %w", d, ctx.Err())
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,648 @@
+package kubectl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"kubetbe/msg"
+	"kubetbe/utils"
+)
+
+// The structs below decode only the fields the lookup needs from
+// `kubectl get ... -o json`. Everything else is ignored.
+
+type objectList struct {
+	Items []object `json:"items"`
+}
+
+type object struct {
+	Kind     string     `json:"kind"`
+	Metadata objectMeta `json:"metadata"`
+	Spec     objectSpec `json:"spec"`
+	Status   struct {
+		Phase  string `json:"phase"` // Pod
+		PodIP  string `json:"podIP"`
+		PodIPs []struct {
+			IP string `json:"ip"`
+		} `json:"podIPs"`
+		Addresses []struct {
+			Type    string `json:"type"`
+			Address string `json:"address"`
+		} `json:"addresses"`
+		LoadBalancer struct {
+			Ingress []struct {
+				IP       string `json:"ip"`
+				Hostname string `json:"hostname"`
+			} `json:"ingress"`
+		} `json:"loadBalancer"`
+	} `json:"status"`
+	Subsets []struct {
+		Addresses         []endpointAddress `json:"addresses"`
+		NotReadyAddresses []endpointAddress `json:"notReadyAddresses"`
+	} `json:"subsets"`
+}
+
+type objectMeta struct {
+	Name            string            `json:"name"`
+	Namespace       string            `json:"namespace"`
+	Labels          map[string]string `json:"labels"`
+	OwnerReferences []struct {
+		Kind       string `json:"kind"`
+		Name       string `json:"name"`
+		Controller bool   `json:"controller"`
+	} `json:"ownerReferences"`
+}
+
+type objectSpec struct {
+	// Service
+	ClusterIP      string            `json:"clusterIP"`
+	ClusterIPs     []string          `json:"clusterIPs"`
+	ExternalIPs    []string          `json:"externalIPs"`
+	LoadBalancerIP string            `json:"loadBalancerIP"`
+	ExternalName   string            `json:"externalName"`
+	Selector       map[string]string `json:"selector"`
+	Ports          []struct {
+		Name       string      `json:"name"`
+		Protocol   string      `json:"protocol"`
+		Port       int         `json:"port"`
+		TargetPort intOrString `json:"targetPort"`
+		NodePort   int         `json:"nodePort"`
+	} `json:"ports"`
+
+	// Pod
+	Containers []struct {
+		Name  string `json:"name"`
+		Ports []struct {
+			Name          string `json:"name"`
+			Protocol      string `json:"protocol"`
+			ContainerPort int    `json:"containerPort"`
+			HostPort      int    `json:"hostPort"`
+		} `json:"ports"`
+	} `json:"containers"`
+
+	// Ingress
+	Rules []struct {
+		Host string `json:"host"`
+	} `json:"rules"`
+	TLS []struct {
+		Hosts []string `json:"hosts"`
+	} `json:"tls"`
+}
+
+// intOrString decodes Kubernetes IntOrString fields such as a Service
+// targetPort, which may be a number or a named container port.
+type intOrString string
+
+func (v *intOrString) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*v = intOrString(s)
+		return nil
+	}
+	var n int
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+	*v = intOrString(strconv.Itoa(n))
+	return nil
+}
+
+type endpointAddress struct {
+	IP        string `json:"ip"`
+	TargetRef *struct {
+		Kind string `json:"kind"`
+		Name string `json:"name"`
+	} `json:"targetRef"`
+}
+
+// ipMatcher reports whether an address matches the query. The query is
+// either a single IP (compared exactly) or a CIDR block.
+type ipMatcher func(addr string) bool
+
+func newIPMatcher(query string) (ipMatcher, error) {
+	if strings.Contains(query, "/") {
+		_, network, err := net.ParseCIDR(query)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q", query)
+		}
+		return func(addr string) bool {
+			ip := net.ParseIP(addr)
+			return ip != nil && network.Contains(ip)
+		}, nil
+	}
+	want := net.ParseIP(query)
+	if want == nil {
+		return nil, fmt.Errorf("invalid IP address %q", query)
+	}
+	return func(addr string) bool {
+		ip := net.ParseIP(addr)
+		return ip != nil && ip.Equal(want)
+	}, nil
+}
+
+// Lookup resolves a query typed into the lookup prompt. The query may be an
+// IP address, a CIDR block, a port number, a Service DNS name
+// (name.namespace[.svc[.cluster.local]]), a pod DNS name, or an ingress
+// hostname. When some kinds could not be listed, the matches among the rest
+// come with an ErrIncomplete.
+func Lookup(ctx context.Context, f Flags, query string) ([]msg.LookupMatch, error) {
+	query = strings.TrimSpace(query)
+	switch {
+	case query == "":
+		return nil, fmt.Errorf("please enter an IP, CIDR, DNS name or port")
+	case isPortQuery(query):
+		return LookupPort(ctx, f, query)
+	case net.ParseIP(query) != nil || strings.Contains(query, "/"):
+		return LookupIP(ctx, f, query)
+	default:
+		return LookupHost(ctx, f, query)
+	}
+}
+
+// LookupIP finds services, pods, endpoints and nodes that use the given IP
+// address or an address inside the given CIDR block.
+func LookupIP(ctx context.Context, f Flags, query string) ([]msg.LookupMatch, error) {
+	match, err := newIPMatcher(query)
+	if err != nil {
+		return nil, err
+	}
+	items, err := getObjects(ctx, f, "services", "endpoints", "pods", "nodes")
+	if items == nil {
+		return nil, err
+	}
+	return sortMatches(matchIP(items, match)), err
+}
+
+// LookupPort finds services and pods exposing the given port, either as a
+// service port, target port, node port, container port or host port.
+func LookupPort(ctx context.Context, f Flags, query string) ([]msg.LookupMatch, error) {
+	port, err := strconv.Atoi(strings.TrimPrefix(query, ":"))
+	if err != nil || port < 1 || port > 65535 {
+		return nil, fmt.Errorf("invalid port %q", query)
+	}
+	items, err := getObjects(ctx, f, "services", "pods")
+	if items == nil {
+		return nil, err
+	}
+	return sortMatches(matchPort(items, port)), err
+}
+
+// LookupHost finds services addressed by a cluster DNS name, pods addressed
+// by a pod DNS name, and ingresses or services serving a hostname.
+func LookupHost(ctx context.Context, f Flags, query string) ([]msg.LookupMatch, error) {
+	host := strings.ToLower(strings.TrimSuffix(query, "."))
+	if strings.ContainsAny(host, " \t/") {
+		return nil, fmt.Errorf("invalid hostname %q", query)
+	}
+	items, err := getObjects(ctx, f, "services", "pods", "ingresses")
+	if items == nil {
+		return nil, err
+	}
+	return sortMatches(matchHost(items, host)), err
+}
+
+// ErrIncomplete marks a lookup that left out kinds it could not list, such
+// as nodes for users bound to the built-in view role. Its matches cover
+// the kinds that could be listed.
+var ErrIncomplete = errors.New("some resource kinds could not be listed")
+
+// getObjects lists every object of kinds in all namespaces. Each kind is
+// its own kubectl call, so one that is denied or fails does not hide the
+// others: the objects found are returned with an ErrIncomplete naming the
+// kinds left out. Only when no kind could be listed are the objects nil.
+func getObjects(ctx context.Context, f Flags, kinds ...string) ([]object, error) {
+	lists := make([][]object, len(kinds))
+	errs := make([]error, len(kinds))
+	var wg sync.WaitGroup
+	for i, kind := range kinds {
+		wg.Add(1)
+		go func(i int, kind string) {
+			defer wg.Done()
+			lists[i], errs[i] = getKind(ctx, f, kind)
+		}(i, kind)
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	items := []object{}
+	var skipped []string
+	var firstErr error
+	for i, kind := range kinds {
+		if errs[i] != nil {
+			reason := skipReason(errs[i])
+			if Classify(errs[i]) == ErrNotFound {
+				reason = "not served by this cluster"
+			}
+			skipped = append(skipped, kind+": "+reason)
+			if firstErr == nil {
+				firstErr = errs[i]
+			}
+			continue
+		}
+		items = append(items, lists[i]...)
+	}
+	switch {
+	case len(skipped) == len(kinds):
+		return nil, fmt.Errorf("failed to fetch cluster resources: %w", firstErr)
+	case len(skipped) > 0:
+		return items, fmt.Errorf("%w: %s", ErrIncomplete, strings.Join(skipped, "; "))
+	}
+	return items, nil
+}
+
+func getKind(ctx context.Context, f Flags, kind string) ([]object, error) {
+	output, err := run(ctx, f, "get", kind, "--all-namespaces", "-o", "json")
+	if err != nil {
+		return nil, err
+	}
+	var list objectList
+	if err := json.Unmarshal(output, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse kubectl output: %w", err)
+	}
+	return list.Items, nil
+}
+
+// skipReason says in a word or a line why a kind or a cluster could not be
+// searched.
+func skipReason(err error) string {
+	switch Classify(err) {
+	case ErrForbidden:
+		return "forbidden"
+	case ErrUnreachable:
+		return "unreachable"
+	}
+	var kerr *Error
+	if errors.As(err, &kerr) && kerr.Stderr != "" {
+		return utils.FirstLine(kerr.Stderr)
+	}
+	return utils.FirstLine(err.Error())
+}
+
+func isPortQuery(query string) bool {
+	digits := strings.TrimPrefix(query, ":")
+	if digits == "" {
+		return false
+	}
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func matchIP(items []object, match ipMatcher) []msg.LookupMatch {
+	var results []msg.LookupMatch
+	for _, obj := range items {
+		// seen avoids reporting the same address twice for one object,
+		// e.g. a pod's podIP is usually repeated in podIPs.
+		seen := map[string]bool{}
+		add := func(field, addr, owner string) {
+			if addr == "" || seen[field+"/"+addr] || !match(addr) {
+				return
+			}
+			seen[field+"/"+addr] = true
+			results = append(results, msg.LookupMatch{
+				Kind:      obj.Kind,
+				Namespace: obj.Metadata.Namespace,
+				Name:      obj.Metadata.Name,
+				Field:     field,
+				Address:   addr,
+				Owner:     owner,
+				Selector:  selectorString(obj.Spec.Selector),
+			})
+		}
+
+		owner := ownerOf(obj.Metadata)
+		switch obj.Kind {
+		case "Service":
+			add("ClusterIP", obj.Spec.ClusterIP, owner)
+			for _, ip := range obj.Spec.ClusterIPs {
+				add("ClusterIP", ip, owner)
+			}
+			for _, ip := range obj.Spec.ExternalIPs {
+				add("ExternalIP", ip, owner)
+			}
+			add("LoadBalancerIP", obj.Spec.LoadBalancerIP, owner)
+			for _, ing := range obj.Status.LoadBalancer.Ingress {
+				add("LoadBalancer", ing.IP, owner)
+			}
+		case "Pod":
+			add("PodIP", obj.Status.PodIP, owner)
+			for _, ip := range obj.Status.PodIPs {
+				add("PodIP", ip.IP, owner)
+			}
+		case "Endpoints":
+			// Endpoints share the name of their Service; the target ref
+			// names the pod actually behind the address.
+			for _, subset := range obj.Subsets {
+				for _, a := range subset.Addresses {
+					add("Endpoint", a.IP, targetRefOwner(a, obj.Metadata.Name))
+				}
+				for _, a := range subset.NotReadyAddresses {
+					add("Endpoint (not ready)", a.IP, targetRefOwner(a, obj.Metadata.Name))
+				}
+			}
+		case "Node":
+			for _, a := range obj.Status.Addresses {
+				add(a.Type, a.Address, owner)
+			}
+		}
+	}
+
+	return results
+}
+
+// matchPort finds service and container ports equal to port.
+func matchPort(items []object, port int) []msg.LookupMatch {
+	var results []msg.LookupMatch
+	for _, obj := range items {
+		add := func(field, addr string) {
+			results = append(results, msg.LookupMatch{
+				Kind:      obj.Kind,
+				Namespace: obj.Metadata.Namespace,
+				Name:      obj.Metadata.Name,
+				Field:     field,
+				Address:   addr,
+				Owner:     ownerOf(obj.Metadata),
+				Selector:  selectorString(obj.Spec.Selector),
+			})
+		}
+
+		switch obj.Kind {
+		case "Service":
+			for _, p := range obj.Spec.Ports {
+				addr := fmt.Sprintf("%d/%s", p.Port, protocolOrTCP(p.Protocol))
+				if p.TargetPort != "" {
+					addr += " -> " + string(p.TargetPort)
+				}
+				switch {
+				case p.Port == port:
+					add("Port", addr)
+				case string(p.TargetPort) == strconv.Itoa(port):
+					add("TargetPort", addr)
+				case p.NodePort == port:
+					add("NodePort", fmt.Sprintf("%d/%s", p.NodePort, protocolOrTCP(p.Protocol)))
+				}
+			}
+		case "Pod":
+			for _, c := range obj.Spec.Containers {
+				for _, p := range c.Ports {
+					addr := fmt.Sprintf("%d/%s (%s)", p.ContainerPort, protocolOrTCP(p.Protocol), c.Name)
+					if p.ContainerPort == port {
+						add("ContainerPort", addr)
+					} else if p.HostPort == port {
+						add("HostPort", fmt.Sprintf("%d/%s (%s)", p.HostPort, protocolOrTCP(p.Protocol), c.Name))
+					}
+				}
+			}
+		}
+	}
+	return results
+}
+
+// matchHost resolves cluster DNS names and external hostnames.
+func matchHost(items []object, host string) []msg.LookupMatch {
+	labels := strings.Split(host, ".")
+
+	// name.namespace, name.namespace.svc, name.namespace.svc.<cluster domain>
+	svcName, svcNamespace := "", ""
+	if len(labels) == 2 || (len(labels) >= 3 && labels[2] == "svc") {
+		svcName, svcNamespace = labels[0], labels[1]
+	}
+
+	// 10-1-2-3.namespace.pod[.<cluster domain>]
+	var podIP net.IP
+	podNamespace := ""
+	if len(labels) >= 3 && labels[2] == "pod" {
+		podIP = net.ParseIP(strings.ReplaceAll(labels[0], "-", "."))
+		podNamespace = labels[1]
+	}
+
+	var results []msg.LookupMatch
+	for _, obj := range items {
+		add := func(field, addr string) {
+			results = append(results, msg.LookupMatch{
+				Kind:      obj.Kind,
+				Namespace: obj.Metadata.Namespace,
+				Name:      obj.Metadata.Name,
+				Field:     field,
+				Address:   addr,
+				Owner:     ownerOf(obj.Metadata),
+				Selector:  selectorString(obj.Spec.Selector),
+			})
+		}
+
+		switch obj.Kind {
+		case "Service":
+			if obj.Metadata.Name == svcName && obj.Metadata.Namespace == svcNamespace {
+				add("DNS", fmt.Sprintf("%s.%s.svc", svcName, svcNamespace))
+			}
+			if strings.EqualFold(obj.Spec.ExternalName, host) {
+				add("ExternalName", obj.Spec.ExternalName)
+			}
+			for _, ing := range obj.Status.LoadBalancer.Ingress {
+				if strings.EqualFold(ing.Hostname, host) {
+					add("LoadBalancer", ing.Hostname)
+				}
+			}
+		case "Pod":
+			if podIP != nil && obj.Metadata.Namespace == podNamespace && net.ParseIP(obj.Status.PodIP).Equal(podIP) {
+				add("DNS", obj.Status.PodIP)
+			}
+		case "Ingress":
+			seen := map[string]bool{}
+			for _, rule := range obj.Spec.Rules {
+				if hostMatches(rule.Host, host) && !seen[rule.Host] {
+					seen[rule.Host] = true
+					add("Host", rule.Host)
+				}
+			}
+			for _, tls := range obj.Spec.TLS {
+				for _, h := range tls.Hosts {
+					if hostMatches(h, host) && !seen[h] {
+						seen[h] = true
+						add("TLS host", h)
+					}
+				}
+			}
+		}
+	}
+	return results
+}
+
+// hostMatches compares an ingress host, which may be a single-label
+// wildcard such as *.example.com, against a hostname.
+func hostMatches(pattern, host string) bool {
+	pattern = strings.ToLower(pattern)
+	if pattern == host {
+		return true
+	}
+	if strings.HasPrefix(pattern, "*.") {
+		suffix := pattern[1:]
+		rest := strings.TrimSuffix(host, suffix)
+		return rest != host && rest != "" && !strings.Contains(rest, ".")
+	}
+	return false
+}
+
+func protocolOrTCP(protocol string) string {
+	if protocol == "" {
+		return "TCP"
+	}
+	return protocol
+}
+
+// selectorString renders a Service selector in kubectl's -l syntax.
+func selectorString(selector map[string]string) string {
+	keys := make([]string, 0, len(selector))
+	for k := range selector {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, k+"="+selector[k])
+	}
+	return strings.Join(parts, ",")
+}
+
+func sortMatches(results []msg.LookupMatch) []msg.LookupMatch {
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Kind != results[j].Kind {
+			return lookupKindOrder(results[i].Kind) < lookupKindOrder(results[j].Kind)
+		}
+		if results[i].Namespace != results[j].Namespace {
+			return results[i].Namespace < results[j].Namespace
+		}
+		return results[i].Name < results[j].Name
+	})
+	return results
+}
+
+func ownerOf(meta objectMeta) string {
+	for _, ref := range meta.OwnerReferences {
+		if ref.Controller {
+			return ref.Kind + "/" + ref.Name
+		}
+	}
+	if len(meta.OwnerReferences) > 0 {
+		return meta.OwnerReferences[0].Kind + "/" + meta.OwnerReferences[0].Name
+	}
+	return ""
+}
+
+func targetRefOwner(a endpointAddress, service string) string {
+	if a.TargetRef != nil {
+		return a.TargetRef.Kind + "/" + a.TargetRef.Name
+	}
+	return "Service/" + service
+}
+
+func lookupKindOrder(kind string) int {
+	switch kind {
+	case "Service":
+		return 0
+	case "Ingress":
+		return 1
+	case "Endpoints":
+		return 2
+	case "Pod":
+		return 3
+	case "Node":
+		return 4
+	}
+	return 5
+}
+
+// LookupMulti runs Lookup in every context at once and merges the matches,
+// each tagged with its context. A cluster that cannot be searched is named
+// in an ErrIncomplete and leaves the others alone; only when none can be
+// searched is the first cluster's error returned as it is.
+func LookupMulti(ctx context.Context, f Flags, contexts []string, query string) ([]msg.LookupMatch, error) {
+	lists := make([][]msg.LookupMatch, len(contexts))
+	errs := make([]error, len(contexts))
+	var wg sync.WaitGroup
+	for i, name := range contexts {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			cf := f
+			cf.Context = name
+			lists[i], errs[i] = Lookup(ctx, cf, query)
+			for j := range lists[i] {
+				lists[i][j].Context = name
+			}
+		}(i, name)
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	merged := []msg.LookupMatch{}
+	var skipped []string
+	failed := 0
+	for i, name := range contexts {
+		merged = append(merged, lists[i]...)
+		switch {
+		case errs[i] == nil:
+		case errors.Is(errs[i], ErrIncomplete):
+			skipped = append(skipped, name+": "+strings.TrimPrefix(errs[i].Error(), ErrIncomplete.Error()+": "))
+		default:
+			failed++
+			skipped = append(skipped, name+": "+skipReason(errs[i]))
+		}
+	}
+	switch {
+	case len(contexts) > 0 && failed == len(contexts):
+		return nil, errs[0]
+	case len(skipped) > 0:
+		return sortMatches(merged), fmt.Errorf("%w: %s", ErrIncomplete, strings.Join(skipped, "; "))
+	}
+	return sortMatches(merged), nil
+}
+
+// FindResource runs Lookup in the background for the lookup prompt.
+func FindResource(ctx context.Context, f Flags, query string) tea.Cmd {
+	return func() tea.Msg {
+		matches, err := Lookup(ctx, f, query)
+		if ctx.Err() != nil {
+			return nil
+		}
+		return lookupMsg(query, matches, err)
+	}
+}
+
+// FindResourceMulti is FindResource for several contexts.
+func FindResourceMulti(ctx context.Context, f Flags, contexts []string, query string) tea.Cmd {
+	return func() tea.Msg {
+		matches, err := LookupMulti(ctx, f, contexts, query)
+		if ctx.Err() != nil {
+			return nil
+		}
+		return lookupMsg(query, matches, err)
+	}
+}
+
+func lookupMsg(query string, matches []msg.LookupMatch, err error) msg.ServiceLookupMsg {
+	if errors.Is(err, ErrIncomplete) {
+		return msg.ServiceLookupMsg{Query: query, Matches: matches, ListErr: err}
+	}
+	return msg.ServiceLookupMsg{
+		Query:   query,
+		Matches: matches,
+		Err:     err,
+	}
+}
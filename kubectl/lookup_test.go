@@ -0,0 +1,232 @@
+package kubectl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"kubetbe/msg"
+)
+
+// clusterJSON is trimmed `kubectl get ... --all-namespaces -o json` output
+// with one object of each kind the lookup reads.
+const clusterJSON = `{"items": [
+	{"kind": "Service", "metadata": {"name": "web", "namespace": "shop"},
+	 "spec": {"clusterIP": "10.96.0.10", "clusterIPs": ["10.96.0.10"], "selector": {"tier": "frontend", "app": "web"},
+	  "ports": [{"name": "http", "protocol": "TCP", "port": 80, "targetPort": 8080, "nodePort": 30080}]},
+	 "status": {"loadBalancer": {"ingress": [{"ip": "203.0.113.7", "hostname": "web.lb.example.com"}]}}},
+	{"kind": "Service", "metadata": {"name": "billing", "namespace": "shop"},
+	 "spec": {"type": "ExternalName", "externalName": "billing.example.net"}},
+	{"kind": "Endpoints", "metadata": {"name": "web", "namespace": "shop"},
+	 "subsets": [{"addresses": [{"ip": "10.244.1.5", "targetRef": {"kind": "Pod", "name": "web-7d9f8b6c5-x2x9k"}}],
+	  "notReadyAddresses": [{"ip": "10.244.2.9"}]}]},
+	{"kind": "Pod", "metadata": {"name": "web-7d9f8b6c5-x2x9k", "namespace": "shop",
+	  "ownerReferences": [{"kind": "ReplicaSet", "name": "web-7d9f8b6c5", "controller": true}]},
+	 "spec": {"containers": [{"name": "web", "ports": [{"containerPort": 8080, "protocol": "TCP"}, {"containerPort": 9090, "hostPort": 9443}]}]},
+	 "status": {"podIP": "10.244.1.5", "podIPs": [{"ip": "10.244.1.5"}]}},
+	{"kind": "Node", "metadata": {"name": "node-1"},
+	 "status": {"addresses": [{"type": "InternalIP", "address": "192.168.1.10"}, {"type": "Hostname", "address": "node-1"}]}},
+	{"kind": "Ingress", "metadata": {"name": "web", "namespace": "shop"},
+	 "spec": {"rules": [{"host": "shop.example.com"}, {"host": "*.apps.example.com"}],
+	  "tls": [{"hosts": ["shop.example.com"]}]}}
+]}`
+
+func clusterObjects(t *testing.T) []object {
+	t.Helper()
+	var list objectList
+	if err := json.Unmarshal([]byte(clusterJSON), &list); err != nil {
+		t.Fatalf("fixture: %v", err)
+	}
+	return list.Items
+}
+
+const webSelector = "app=web,tier=frontend"
+
+func TestMatchIP(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []msg.LookupMatch
+	}{
+		{"10.96.0.10", []msg.LookupMatch{
+			{Kind: "Service", Namespace: "shop", Name: "web", Field: "ClusterIP", Address: "10.96.0.10", Selector: webSelector},
+		}},
+		{"203.0.113.7", []msg.LookupMatch{
+			{Kind: "Service", Namespace: "shop", Name: "web", Field: "LoadBalancer", Address: "203.0.113.7", Selector: webSelector},
+		}},
+		{"10.244.1.5", []msg.LookupMatch{
+			{Kind: "Endpoints", Namespace: "shop", Name: "web", Field: "Endpoint", Address: "10.244.1.5", Owner: "Pod/web-7d9f8b6c5-x2x9k"},
+			{Kind: "Pod", Namespace: "shop", Name: "web-7d9f8b6c5-x2x9k", Field: "PodIP", Address: "10.244.1.5", Owner: "ReplicaSet/web-7d9f8b6c5"},
+		}},
+		{"10.244.2.9", []msg.LookupMatch{
+			{Kind: "Endpoints", Namespace: "shop", Name: "web", Field: "Endpoint (not ready)", Address: "10.244.2.9", Owner: "Service/web"},
+		}},
+		{"192.168.1.10", []msg.LookupMatch{
+			{Kind: "Node", Name: "node-1", Field: "InternalIP", Address: "192.168.1.10"},
+		}},
+		{"10.244.0.0/16", []msg.LookupMatch{
+			{Kind: "Endpoints", Namespace: "shop", Name: "web", Field: "Endpoint", Address: "10.244.1.5", Owner: "Pod/web-7d9f8b6c5-x2x9k"},
+			{Kind: "Endpoints", Namespace: "shop", Name: "web", Field: "Endpoint (not ready)", Address: "10.244.2.9", Owner: "Service/web"},
+			{Kind: "Pod", Namespace: "shop", Name: "web-7d9f8b6c5-x2x9k", Field: "PodIP", Address: "10.244.1.5", Owner: "ReplicaSet/web-7d9f8b6c5"},
+		}},
+		{"10.0.0.1", nil},
+	}
+	items := clusterObjects(t)
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			match, err := newIPMatcher(tt.query)
+			if err != nil {
+				t.Fatalf("newIPMatcher(%q): %v", tt.query, err)
+			}
+			if got := sortMatches(matchIP(items, match)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("matchIP(%q) =\n%+v\nwant\n%+v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewIPMatcherRejectsBadQueries(t *testing.T) {
+	for _, query := range []string{"10.0.0", "10.0.0.0/33", "web"} {
+		if _, err := newIPMatcher(query); err == nil {
+			t.Errorf("newIPMatcher(%q) succeeded, want an error", query)
+		}
+	}
+}
+
+func TestMatchPort(t *testing.T) {
+	tests := []struct {
+		port int
+		want []msg.LookupMatch
+	}{
+		{80, []msg.LookupMatch{
+			{Kind: "Service", Namespace: "shop", Name: "web", Field: "Port", Address: "80/TCP -> 8080", Selector: webSelector},
+		}},
+		{8080, []msg.LookupMatch{
+			{Kind: "Service", Namespace: "shop", Name: "web", Field: "TargetPort", Address: "80/TCP -> 8080", Selector: webSelector},
+			{Kind: "Pod", Namespace: "shop", Name: "web-7d9f8b6c5-x2x9k", Field: "ContainerPort", Address: "8080/TCP (web)", Owner: "ReplicaSet/web-7d9f8b6c5"},
+		}},
+		{30080, []msg.LookupMatch{
+			{Kind: "Service", Namespace: "shop", Name: "web", Field: "NodePort", Address: "30080/TCP", Selector: webSelector},
+		}},
+		{9443, []msg.LookupMatch{
+			{Kind: "Pod", Namespace: "shop", Name: "web-7d9f8b6c5-x2x9k", Field: "HostPort", Address: "9443/TCP (web)", Owner: "ReplicaSet/web-7d9f8b6c5"},
+		}},
+		{443, nil},
+	}
+	items := clusterObjects(t)
+	for _, tt := range tests {
+		if got := sortMatches(matchPort(items, tt.port)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("matchPort(%d) =\n%+v\nwant\n%+v", tt.port, got, tt.want)
+		}
+	}
+}
+
+func TestMatchHost(t *testing.T) {
+	tests := []struct {
+		host string
+		want []msg.LookupMatch
+	}{
+		{"web.shop", []msg.LookupMatch{
+			{Kind: "Service", Namespace: "shop", Name: "web", Field: "DNS", Address: "web.shop.svc", Selector: webSelector},
+		}},
+		{"web.shop.svc.cluster.local", []msg.LookupMatch{
+			{Kind: "Service", Namespace: "shop", Name: "web", Field: "DNS", Address: "web.shop.svc", Selector: webSelector},
+		}},
+		{"web.other.svc", nil},
+		{"billing.example.net", []msg.LookupMatch{
+			{Kind: "Service", Namespace: "shop", Name: "billing", Field: "ExternalName", Address: "billing.example.net"},
+		}},
+		{"web.lb.example.com", []msg.LookupMatch{
+			{Kind: "Service", Namespace: "shop", Name: "web", Field: "LoadBalancer", Address: "web.lb.example.com", Selector: webSelector},
+		}},
+		{"10-244-1-5.shop.pod.cluster.local", []msg.LookupMatch{
+			{Kind: "Pod", Namespace: "shop", Name: "web-7d9f8b6c5-x2x9k", Field: "DNS", Address: "10.244.1.5", Owner: "ReplicaSet/web-7d9f8b6c5"},
+		}},
+		{"shop.example.com", []msg.LookupMatch{
+			{Kind: "Ingress", Namespace: "shop", Name: "web", Field: "Host", Address: "shop.example.com"},
+		}},
+		{"admin.apps.example.com", []msg.LookupMatch{
+			{Kind: "Ingress", Namespace: "shop", Name: "web", Field: "Host", Address: "*.apps.example.com"},
+		}},
+		// A wildcard covers exactly one label
+		{"a.b.apps.example.com", nil},
+		{"apps.example.com", nil},
+	}
+	items := clusterObjects(t)
+	for _, tt := range tests {
+		if got := sortMatches(matchHost(items, tt.host)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("matchHost(%q) =\n%+v\nwant\n%+v", tt.host, got, tt.want)
+		}
+	}
+}
+
+// kindLists answers `kubectl get <kind> --all-namespaces -o json` with a
+// service and a pod; listing nodes is forbidden, as for the built-in view
+// role.
+const kindLists = `case "$2" in
+services) echo '{"items": [{"kind": "Service", "metadata": {"name": "web", "namespace": "shop"}, "spec": {"clusterIP": "10.96.0.10"}}]}' ;;
+endpoints) echo '{"items": []}' ;;
+pods) echo '{"items": [{"kind": "Pod", "metadata": {"name": "web-0", "namespace": "shop"}, "status": {"podIP": "10.244.1.5"}}]}' ;;
+nodes) echo 'Error from server (Forbidden): nodes is forbidden: User "jane" cannot list resource "nodes" in API group "" at the cluster scope' >&2; exit 1 ;;
+esac`
+
+func TestLookupIPKeepsWhatCouldBeListed(t *testing.T) {
+	fakeKubectl(t, kindLists)
+
+	matches, err := LookupIP(context.Background(), Flags{}, "10.0.0.0/8")
+	if !errors.Is(err, ErrIncomplete) {
+		t.Fatalf("err = %v, want ErrIncomplete", err)
+	}
+	if want := "nodes: forbidden"; !strings.HasSuffix(err.Error(), want) {
+		t.Errorf("err = %q, want it to end in %q", err, want)
+	}
+	want := []msg.LookupMatch{
+		{Kind: "Service", Namespace: "shop", Name: "web", Field: "ClusterIP", Address: "10.96.0.10"},
+		{Kind: "Pod", Namespace: "shop", Name: "web-0", Field: "PodIP", Address: "10.244.1.5"},
+	}
+	if !reflect.DeepEqual(matches, want) {
+		t.Errorf("matches =\n%+v\nwant\n%+v", matches, want)
+	}
+}
+
+func TestLookupFailsWhenNothingCouldBeListed(t *testing.T) {
+	fakeKubectl(t, "echo 'error: You must be logged in to the server (Unauthorized)' >&2; exit 1")
+
+	matches, err := LookupIP(context.Background(), Flags{}, "10.0.0.1")
+	if err == nil || errors.Is(err, ErrIncomplete) {
+		t.Fatalf("err = %v, want a plain failure", err)
+	}
+	if Classify(err) != ErrAuthExpired {
+		t.Errorf("Classify = %v, want %v", Classify(err), ErrAuthExpired)
+	}
+	if matches != nil {
+		t.Errorf("matches = %+v, want none", matches)
+	}
+}
+
+func TestLookupMultiTagsMatchesWithTheirCluster(t *testing.T) {
+	fakeKubectl(t, `case "$2:$4" in
+stage:pods) echo '{"items": [{"kind": "Pod", "metadata": {"name": "web-0", "namespace": "shop"}, "status": {"podIP": "10.244.1.5"}}]}' ;;
+prod:pods) echo '{"items": [{"kind": "Pod", "metadata": {"name": "web-7", "namespace": "shop"}, "status": {"podIP": "10.244.1.5"}}]}' ;;
+down:*) echo 'Unable to connect to the server: dial tcp 10.1.1.1:443: i/o timeout' >&2; exit 1 ;;
+*) echo '{"items": []}' ;;
+esac`)
+
+	matches, err := LookupMulti(context.Background(), Flags{}, []string{"stage", "down", "prod"}, "10.244.1.5")
+	if !errors.Is(err, ErrIncomplete) || !strings.HasSuffix(err.Error(), "down: unreachable") {
+		t.Errorf("err = %v, want ErrIncomplete naming the unreachable cluster", err)
+	}
+	want := []msg.LookupMatch{
+		{Kind: "Pod", Namespace: "shop", Name: "web-0", Field: "PodIP", Address: "10.244.1.5", Context: "stage"},
+		{Kind: "Pod", Namespace: "shop", Name: "web-7", Field: "PodIP", Address: "10.244.1.5", Context: "prod"},
+	}
+	if !reflect.DeepEqual(matches, want) {
+		t.Errorf("matches =\n%+v\nwant\n%+v", matches, want)
+	}
+
+	if _, err := LookupMulti(context.Background(), Flags{}, []string{"down"}, "10.244.1.5"); err == nil || errors.Is(err, ErrIncomplete) {
+		t.Errorf("err = %v, want the cluster's own failure when no cluster could be searched", err)
+	}
+}
@@ -0,0 +1,140 @@
+package kubectl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/user"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"kubetbe/audit"
+)
+
+// ErrReadOnly is returned by every mutating call while read-only mode is on.
+var ErrReadOnly = errors.New("refused: kubetbe is running in read-only mode")
+
+var readOnly atomic.Bool
+
+// SetReadOnly turns read-only mode on or off. In read-only mode every
+// command that would change cluster state fails with ErrReadOnly before
+// kubectl is started.
+func SetReadOnly(on bool) {
+	readOnly.Store(on)
+}
+
+// ReadOnly reports whether read-only mode is on.
+func ReadOnly() bool {
+	return readOnly.Load()
+}
+
+// target names what a mutating command acts on, for the audit log.
+type target struct {
+	Namespace string
+	Resource  string // kind/name, e.g. pod/web-1
+}
+
+// mutate runs a kubectl command that changes cluster state. Every mutating
+// call must go through here so read-only mode and the audit log are
+// enforced in one place, whatever the UI does. If the audit log cannot be
+// opened the command is not run.
+func mutate(ctx context.Context, f Flags, t target, args ...string) ([]byte, error) {
+	return mutateInput(ctx, f, t, nil, args...)
+}
+
+// mutateInput is mutate with stdin for the command. The command runs
+// under the write timeout.
+func mutateInput(ctx context.Context, f Flags, t target, stdin []byte, args ...string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err // never started, nothing to record
+	}
+	log, err := audit.Open()
+	if err != nil {
+		return nil, fmt.Errorf("refused: %w", err)
+	}
+	defer log.Close()
+
+	entry := audit.Entry{
+		Time:      time.Now(),
+		User:      currentUser(),
+		Context:   contextOf(f),
+		Namespace: t.Namespace,
+		Resource:  t.Resource,
+		Command:   commandLine(append(f.args(), args...)),
+	}
+
+	if ReadOnly() {
+		entry.ExitCode = -1
+		entry.Error = ErrReadOnly.Error()
+		if aerr := log.Append(entry); aerr != nil {
+			return nil, errors.Join(ErrReadOnly, aerr)
+		}
+		return nil, ErrReadOnly
+	}
+
+	d := currentTimeouts().Write
+	ctx, cancel := withTimeout(ctx, d)
+	defer cancel()
+	output, stderr, err := runWith(ctx, f, stdin, args...)
+	err = interrupted(ctx, d, err)
+	entry.ExitCode = exitCode(err)
+	entry.Stderr = strings.TrimSpace(string(stderr))
+	if err != nil {
+		entry.Error = err.Error()
+		err = failed(f, args, stderr, err)
+	}
+	if aerr := log.Append(entry); aerr != nil {
+		if err == nil {
+			return output, fmt.Errorf("%s succeeded but was not recorded: %w", t.Resource, aerr)
+		}
+		err = errors.Join(err, aerr)
+	}
+
+	return output, err
+}
+
+var (
+	contextOnce sync.Once
+	contextName string
+)
+
+// contextOf returns the kube context a call with the global flags f
+// reaches: the one passed with --context, or the kubeconfig's current
+// context, looked up once.
+func contextOf(f Flags) string {
+	if f.Context != "" {
+		return f.Context
+	}
+	contextOnce.Do(func() {
+		out, err := run(context.Background(), Flags{Kubeconfig: f.Kubeconfig}, "config", "current-context")
+		if err != nil {
+			contextName = "unknown"
+			return
+		}
+		contextName = strings.TrimSpace(string(out))
+	})
+	return contextName
+}
+
+// currentUser returns the local login name of whoever runs kubetbe.
+func currentUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return "unknown"
+}
+
+// commandLine renders args as the kubectl command line that was run.
+func commandLine(args []string) string {
+	quoted := make([]string, 0, len(args)+1)
+	quoted = append(quoted, "kubectl")
+	for _, a := range args {
+		if a == "" || strings.ContainsAny(a, " \t\"'") {
+			a = fmt.Sprintf("%q", a)
+		}
+		quoted = append(quoted, a)
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,113 @@
+package kubectl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"kubetbe/msg"
+)
+
+// podFailureReasons are container waiting reasons that mean a pod is stuck
+// rather than merely starting up.
+var podFailureReasons = map[string]bool{
+	"CrashLoopBackOff":           true,
+	"ImagePullBackOff":           true,
+	"ErrImagePull":               true,
+	"InvalidImageName":           true,
+	"CreateContainerConfigError": true,
+	"CreateContainerError":       true,
+	"RunContainerError":          true,
+}
+
+// podSummaryTemplate prints one line per pod: namespace, phase and the
+// waiting reasons of its containers. This is much smaller than the full
+// JSON for clusters with thousands of pods.
+const podSummaryTemplate = `{range .items[*]}{.metadata.namespace}{"\t"}{.status.phase}{"\t"}` +
+	`{.status.initContainerStatuses[*].state.waiting.reason} {.status.containerStatuses[*].state.waiting.reason}{"\n"}{end}`
+
+// GetNamespaces lists all namespaces with their phase, creation time,
+// labels and a count of running, pending and failing pods. If pods cannot
+// be listed cluster-wide, namespaces are still returned with PodsKnown
+// unset.
+func GetNamespaces(ctx context.Context, f Flags) ([]msg.NamespaceInfo, error) {
+	output, err := run(ctx, f, "get", "namespaces", "-o", "json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to run kubectl get namespaces command: %w", err)
+	}
+
+	var list struct {
+		Items []struct {
+			Metadata struct {
+				Name              string            `json:"name"`
+				CreationTimestamp time.Time         `json:"creationTimestamp"`
+				Labels            map[string]string `json:"labels"`
+			} `json:"metadata"`
+			Status struct {
+				Phase string `json:"phase"`
+			} `json:"status"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(output, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse namespaces: %w", err)
+	}
+
+	namespaces := make([]msg.NamespaceInfo, 0, len(list.Items))
+	index := map[string]int{}
+	for _, item := range list.Items {
+		index[item.Metadata.Name] = len(namespaces)
+		namespaces = append(namespaces, msg.NamespaceInfo{
+			Name:    item.Metadata.Name,
+			Phase:   item.Status.Phase,
+			Created: item.Metadata.CreationTimestamp,
+			Labels:  item.Metadata.Labels,
+		})
+	}
+
+	pods, err := run(ctx, f, "get", "pods", "--all-namespaces", "-o", "jsonpath="+podSummaryTemplate)
+	if err == nil {
+		for _, line := range strings.Split(string(pods), "\n") {
+			fields := strings.SplitN(line, "\t", 3)
+			if len(fields) < 2 {
+				continue
+			}
+			i, ok := index[fields[0]]
+			if !ok {
+				continue
+			}
+			reasons := ""
+			if len(fields) == 3 {
+				reasons = fields[2]
+			}
+			countPod(&namespaces[i], fields[1], strings.Fields(reasons))
+		}
+		for i := range namespaces {
+			namespaces[i].PodsKnown = true
+		}
+	}
+
+	sort.Slice(namespaces, func(i, j int) bool {
+		return namespaces[i].Name < namespaces[j].Name
+	})
+	return namespaces, nil
+}
+
+func countPod(ns *msg.NamespaceInfo, phase string, waitingReasons []string) {
+	for _, reason := range waitingReasons {
+		if podFailureReasons[reason] {
+			ns.Failing++
+			return
+		}
+	}
+	switch phase {
+	case "Running":
+		ns.Running++
+	case "Pending":
+		ns.Pending++
+	case "Failed", "Unknown":
+		ns.Failing++
+	}
+}
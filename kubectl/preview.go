@@ -0,0 +1,110 @@
+package kubectl
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"kubetbe/msg"
+	"kubetbe/utils"
+)
+
+// skipKinds are namespaced kinds left out of delete previews and namespace
+// diagnoses. Events go with the namespace too, but listing them only buries
+// what matters.
+var skipKinds = map[string]bool{
+	"events":               true,
+	"events.events.k8s.io": true,
+}
+
+// PreviewNamespaceDelete runs the namespace delete as a server-side dry run,
+// so admission and RBAC are checked without anything being removed, and
+// lists the namespaced resources the real delete would take with it.
+func PreviewNamespaceDelete(ctx context.Context, f Flags, namespace string) tea.Cmd {
+	return func() tea.Msg {
+		preview := msg.NamespaceDeletePreviewMsg{Namespace: namespace}
+
+		// Deliberately run, not mutate: a server dry run changes nothing, so
+		// it is neither refused in read-only mode nor written to the audit
+		// log. The confirmed delete that may follow goes through mutate.
+		output, err := run(ctx, f, "delete", "namespace", namespace, "--dry-run=server")
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			preview.Err = fmt.Errorf("server dry run failed: %w", err)
+			return preview
+		}
+		preview.DryRun = strings.TrimSpace(string(output))
+
+		preview.Resources, preview.ListErr = namespaceContents(ctx, f, namespace)
+		if ctx.Err() != nil {
+			return nil
+		}
+		return preview
+	}
+}
+
+// getNamespaced runs kubectl get in namespace for every namespaced kind
+// that can be listed and deleted, in the given output format. Kinds that
+// cannot be listed are skipped: the error then says so and the output still
+// covers everything that could be seen.
+func getNamespaced(ctx context.Context, f Flags, namespace, format string) ([]byte, error) {
+	output, err := run(ctx, f, "api-resources", "--namespaced=true", "--verbs=list,delete", "-o", "name")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list resource types: %w", err)
+	}
+	var kinds []string
+	for _, kind := range strings.Fields(string(output)) {
+		if !skipKinds[kind] {
+			kinds = append(kinds, kind)
+		}
+	}
+	if len(kinds) == 0 {
+		return nil, nil
+	}
+
+	output, stderr, err := runCapture(ctx, f, "get", strings.Join(kinds, ","), "-n", namespace, "-o", format)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		listErr := fmt.Errorf("some resource types could not be listed: %s", utils.FirstLine(string(stderr)))
+		if len(output) == 0 {
+			return nil, listErr
+		}
+		return output, listErr
+	}
+	return output, nil
+}
+
+// namespaceContents lists every deletable resource in namespace, grouped by
+// kind. A partial listing is returned along with its error.
+func namespaceContents(ctx context.Context, f Flags, namespace string) ([]msg.ResourceGroup, error) {
+	output, listErr := getNamespaced(ctx, f, namespace, "name")
+	if output == nil {
+		return nil, listErr
+	}
+
+	byKind := map[string][]string{}
+	for _, line := range strings.Split(string(output), "\n") {
+		kind, name, ok := strings.Cut(strings.TrimSpace(line), "/")
+		if !ok {
+			continue
+		}
+		byKind[kind] = append(byKind[kind], name)
+	}
+
+	groups := make([]msg.ResourceGroup, 0, len(byKind))
+	for kind, names := range byKind {
+		sort.Strings(names)
+		groups = append(groups, msg.ResourceGroup{Kind: kind, Names: names})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Kind < groups[j].Kind
+	})
+	return groups, listErr
+}
@@ -0,0 +1,65 @@
+package kubectl
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os/exec"
+)
+
+// run executes kubectl with the global flags f and the given arguments and
+// returns stdout. When kubectl fails, the returned error is an *Error
+// carrying its stderr so the UI can show more than "exit status 1". The call
+// is killed when ctx ends or the read timeout passes.
+func run(ctx context.Context, f Flags, args ...string) ([]byte, error) {
+	output, _, err := runRead(ctx, f, args...)
+	return output, err
+}
+
+// runCapture executes kubectl and returns stdout and stderr separately,
+// for callers that make sense of partial output.
+func runCapture(ctx context.Context, f Flags, args ...string) ([]byte, []byte, error) {
+	return runRead(ctx, f, args...)
+}
+
+// runRead runs a read under the read timeout, retrying while the cluster
+// cannot be reached. Its error is an *Error.
+func runRead(ctx context.Context, f Flags, args ...string) ([]byte, []byte, error) {
+	var output, stderr []byte
+	err := withRetry(ctx, f, func() error {
+		d := currentTimeouts().Read
+		callCtx, cancel := withTimeout(ctx, d)
+		defer cancel()
+		var err error
+		output, stderr, err = runWith(callCtx, f, nil, args...)
+		return failed(f, args, stderr, interrupted(callCtx, d, err))
+	})
+	return output, stderr, err
+}
+
+// runWith runs kubectl with the global flags f and stdin, if not nil,
+// until it exits or ctx ends.
+func runWith(ctx context.Context, f Flags, stdin []byte, args ...string) ([]byte, []byte, error) {
+	cmd := commandFlags(ctx, f, args...)
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	return output, stderr.Bytes(), err
+}
+
+// exitCode returns the exit status of a finished kubectl command: 0 on
+// success, kubectl's status if it ran and failed, or -1 if it never ran.
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
@@ -1,15 +1,46 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 
 	tea "github.com/charmbracelet/bubbletea"
 
+	"kubetbe/cli"
+	"kubetbe/config"
+	"kubetbe/kubectl"
 	"kubetbe/ui"
 )
 
 func main() {
+	configPath := flag.String("config", "", "path to config file (default $"+config.EnvConfig+" or ~/.config/kubetbe/config.yaml)")
+	readOnly := flag.Bool("read-only", false, "disable every action that changes cluster state")
+	showVersion := flag.Bool("version", false, "print version and build information and exit")
+	kubeconfig := flag.String("kubeconfig", os.Getenv(config.EnvKubeconfig), "kubeconfig file for every kubectl call ($"+config.EnvKubeconfig+")")
+	kubeContext := flag.String("context", os.Getenv(config.EnvContext), "kube context for every kubectl call ($"+config.EnvContext+")")
+	namespace := flag.String("namespace", os.Getenv(config.EnvNamespace), "open this namespace straight away ($"+config.EnvNamespace+")")
+	flag.StringVar(namespace, "n", *namespace, "shorthand for --namespace")
+	as := flag.String("as", os.Getenv(config.EnvAs), "user to impersonate in every kubectl call ($"+config.EnvAs+")")
+	contexts := flag.String("contexts", os.Getenv(config.EnvContexts), "comma-separated kube contexts to list namespaces from at once ($"+config.EnvContexts+")")
+	flag.Usage = func() {
+		out := flag.CommandLine.Output()
+		fmt.Fprintf(out, "Usage: kubetbe [flags] [namespace-filter]\n       kubetbe [flags] <command> [args]\n\n")
+		cli.Usage(out)
+		fmt.Fprintf(out, "\nFlags:\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *showVersion {
+		fmt.Print(cli.VersionString())
+		return
+	}
+
 	if len(os.Getenv("DEBUG")) > 0 {
 		f, err := tea.LogToFile("debug.log", "debug")
 		if err != nil {
@@ -19,16 +50,90 @@ func main() {
 		defer f.Close()
 	}
 
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *readOnly {
+		cfg.ReadOnly = true
+	}
+	kubectl.SetReadOnly(cfg.ReadOnly)
+	kubectl.SetFlags(kubectl.Flags{Kubeconfig: *kubeconfig, Context: *kubeContext, As: *as})
+	kubectl.SetTimeouts(kubectl.Timeouts{Read: cfg.Timeouts.Read, Write: cfg.Timeouts.Write})
+	kubectl.SetRetry(kubectl.Retry{Attempts: cfg.Retry.Attempts, Backoff: cfg.Retry.Backoff})
+	if *namespace != "" {
+		cfg.StartupNamespace = *namespace
+		if err := cfg.Validate(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --namespace: %q is not a valid namespace name\n", *namespace)
+			os.Exit(1)
+		}
+	}
+	if *contexts != "" {
+		cfg.Contexts = nil
+		for _, name := range strings.Split(*contexts, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				cfg.Contexts = append(cfg.Contexts, name)
+			}
+		}
+		if err := cfg.Validate(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --contexts: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// A signal kills every kubectl call in flight before kubetbe exits
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	defer stop()
+
+	// Subcommands print and exit without starting the UI
+	if cli.IsCommand(flag.Arg(0)) {
+		os.Exit(cli.Run(cli.NewEnv(ctx, cfg), flag.Args()))
+	}
+
+	keys, err := ui.NewKeymap(cfg.Keys)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: config %s: %v\n", cfg.Path, err)
+		os.Exit(1)
+	}
+	keys.ReadOnly = cfg.ReadOnly
+
+	if err := ui.ApplyTheme(cfg.Theme); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: config %s: %v\n", cfg.Path, err)
+		os.Exit(1)
+	}
+
 	// Get search term from command line arguments
-	searchTerm := ""
-	if len(os.Args) > 1 {
-		searchTerm = os.Args[1]
+	searchTerm := flag.Arg(0)
+
+	// Favorites are a convenience; a broken state file should not stop the app
+	userState, err := config.LoadState()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
 	}
 
-	model := ui.InitialModel(searchTerm)
-	p := tea.NewProgram(model, tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
+	model := ui.InitialModel(ui.Options{
+		SearchTerm: searchTerm,
+		Config:     cfg,
+		Keys:       keys,
+		UserState:  userState,
+		Context:    ctx,
+	})
+	// Signals are handled here rather than by Bubble Tea, so SIGHUP counts
+	// too and the terminal is restored through a normal quit
+	p := tea.NewProgram(model, tea.WithAltScreen(), tea.WithoutSignalHandler())
+	go func() {
+		<-ctx.Done()
+		p.Quit()
+	}()
+	_, err = p.Run()
+	model.Shutdown()
+	if err != nil {
 		fmt.Printf("Error: %v\n", err)
 		os.Exit(1)
 	}
+	if ctx.Err() != nil {
+		os.Exit(130)
+	}
 }
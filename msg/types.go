@@ -1,7 +1,35 @@
 package msg
 
+import (
+	"time"
+
+	"kubetbe/audit"
+)
+
+// NamespaceInfo is a namespace row on the namespace screen.
+type NamespaceInfo struct {
+	Name      string
+	Phase     string // Active or Terminating
+	Created   time.Time
+	Labels    map[string]string
+	PodsKnown bool // false when pods could not be listed
+	Running   int
+	Pending   int
+	Failing   int
+	Context   string // kube context it was listed from, in multi-cluster mode
+}
+
+// ClusterStatus is how one context fared in a multi-cluster listing.
+type ClusterStatus struct {
+	Context    string
+	Namespaces int
+	Err        error // the cluster is degraded: its namespaces are missing
+}
+
 type NamespaceListMsg struct {
-	Namespaces []string
+	Namespaces []NamespaceInfo
+	Context    string          // kube context listed, "" for kubectl's current one
+	Clusters   []ClusterStatus // one per context in multi-cluster mode, nil otherwise
 }
 
 type NamespaceDeleteMsg struct {
@@ -9,12 +37,101 @@ type NamespaceDeleteMsg struct {
 	Err       error
 }
 
+// ResourceGroup is every resource of one kind, e.g. all deployment.apps in
+// a namespace.
+type ResourceGroup struct {
+	Kind  string // resource.group as kubectl prints it, e.g. deployment.apps
+	Names []string
+}
+
+// NamespaceDeletePreviewMsg is the result of a server-side dry-run delete.
+type NamespaceDeletePreviewMsg struct {
+	Namespace string
+	DryRun    string          // what the API server answered, e.g. namespace "x" deleted (server dry run)
+	Resources []ResourceGroup // what the delete would remove
+	Err       error           // the dry run itself failed; the real delete would most likely fail too
+	ListErr   error           // the dry run passed but the resource list is incomplete
+}
+
+// NamespaceCondition is one of a namespace's status conditions, such as
+// NamespaceContentRemaining or NamespaceFinalizersRemaining.
+type NamespaceCondition struct {
+	Type    string
+	Status  string
+	Reason  string
+	Message string
+}
+
+// RemainingResource is a resource still present in a namespace.
+type RemainingResource struct {
+	Group      string // API group, "core" for the core group
+	Ref        string // kind.group/name, as kubectl get -o name prints it
+	Finalizers []string
+	Deleting   bool // has a deletion timestamp
+}
+
+// NamespaceDiagnosisMsg explains what is keeping a namespace around.
+type NamespaceDiagnosisMsg struct {
+	Namespace    string
+	Phase        string
+	DeletionTime time.Time // zero unless the namespace is being deleted
+	Finalizers   []string  // spec.finalizers of the namespace itself
+	Conditions   []NamespaceCondition
+	Resources    []RemainingResource
+	Err          error // the namespace could not be read
+	ListErr      error // the resource list is incomplete
+}
+
+// FinalizersClearedMsg is the result of clearing the finalizers of a
+// resource.
+type FinalizersClearedMsg struct {
+	Namespace string
+	Ref       string
+	Err       error
+}
+
+// AccessMsg reports that the permissions in a namespace have been checked.
+type AccessMsg struct {
+	Context   string // kube context checked, "" for kubectl's current one
+	Namespace string
+}
+
 type PodDeleteMsg struct {
 	Namespace string
 	Pod       string
 	Err       error
 }
 
+// BulkDeleteMsg reports progress of one pod in a bulk delete.
+type BulkDeleteMsg struct {
+	Namespace string
+	Pod       string
+	Started   bool  // kubectl delete started; false once it has finished
+	Err       error // set when the finished delete failed
+}
+
+// BulkDeleteDoneMsg is sent once every pod of a bulk delete has finished.
+type BulkDeleteDoneMsg struct {
+	Namespace string
+}
+
+// DisruptionViolation is a PodDisruptionBudget that removing some pods
+// would violate.
+type DisruptionViolation struct {
+	Budget  string   // PodDisruptionBudget name
+	Allowed int      // disruptions the budget currently allows
+	Pods    []string // pods being removed that the budget covers
+}
+
+// DisruptionCheckMsg is the result of checking pods to be removed against
+// the PodDisruptionBudgets in their namespace.
+type DisruptionCheckMsg struct {
+	Namespace  string
+	Pods       []string
+	Violations []DisruptionViolation
+	Err        error
+}
+
 type PodDescribeMsg struct {
 	Namespace string
 	Pod       string
@@ -22,10 +139,45 @@ type PodDescribeMsg struct {
 	Err       error
 }
 
+// LookupMatch is a single resource that matched a lookup query.
+type LookupMatch struct {
+	Kind      string // Service, Ingress, Pod, Endpoints, Node
+	Namespace string // empty for cluster-scoped resources
+	Name      string
+	Field     string // what matched, e.g. ClusterIP, PodIP, Host, Port
+	Address   string
+	Owner     string // Kind/Name of the owning object, if any
+	Selector  string // label selector of a Service, e.g. "app=web,tier=frontend"
+	Context   string // kube context it was found in, in multi-cluster mode
+}
+
 type ServiceLookupMsg struct {
-	IP     string
-	Result []string
-	Err    error
+	Query   string
+	Matches []LookupMatch
+	Err     error
+	ListErr error // some kinds could not be listed; the matches are incomplete
+}
+
+// AuditLogMsg carries the newest audit log entries, newest first.
+type AuditLogMsg struct {
+	Entries []audit.Entry
+	Err     error
+}
+
+// KubeContext is one context from the kubeconfig.
+type KubeContext struct {
+	Name      string
+	Cluster   string
+	User      string
+	Namespace string // default namespace, if any
+}
+
+// ContextListMsg carries the kubeconfig's contexts. Current is the one
+// kubectl uses when no --context is given.
+type ContextListMsg struct {
+	Contexts []KubeContext
+	Current  string
+	Err      error
 }
 
 type ErrorMsg struct {
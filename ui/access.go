@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"kubetbe/kubectl"
+	"kubetbe/msg"
+)
+
+// Entering a namespace asks kubectl auth can-i what may be done there. An
+// action RBAC denies stays listed, greyed out with the reason, and is
+// refused when pressed instead of starting a delete that cannot succeed.
+// Until the answers are in, every action is allowed.
+
+// namespaceAccess is what is known about the permissions in ns.
+func (m *Model) namespaceAccess(ns msg.NamespaceInfo) kubectl.Access {
+	kubeContext := ns.Context
+	if kubeContext == "" {
+		kubeContext = m.KubeContext
+	}
+	return kubectl.AccessIn(kubeContext, ns.Name)
+}
+
+// denied explains why RBAC does not allow action on what is selected, or
+// returns "" if it does or that is not known.
+func (m *Model) denied(action Action) string {
+	switch m.State {
+	case "namespace_select":
+		if action != ActionDelete || m.Cursor >= len(m.Namespaces) {
+			break
+		}
+		ns := m.Namespaces[m.Cursor]
+		if !m.namespaceAccess(ns).Allows(kubectl.CanDeleteNamespace) {
+			return fmt.Sprintf("RBAC does not let you delete namespace '%s'", ns.Name)
+		}
+	case "panel_view":
+		access := kubectl.AccessIn(m.activeContext(), m.SelectedNS)
+		canDelete := access.Allows(kubectl.CanDeletePods)
+		canEvict := access.Allows(kubectl.CanEvictPods)
+		switch {
+		case action == ActionDelete && !canDelete && canEvict:
+			return fmt.Sprintf("RBAC does not let you delete pods in '%s'; %s can still evict them", m.SelectedNS, m.keyFor(ActionDeleteWith))
+		case action == ActionDelete && !canDelete:
+			return fmt.Sprintf("RBAC does not let you delete pods in '%s'", m.SelectedNS)
+		case action == ActionDeleteWith && !canDelete && !canEvict:
+			return fmt.Sprintf("RBAC lets you neither delete nor evict pods in '%s'", m.SelectedNS)
+		}
+	}
+	return ""
+}
+
+// actionHint is Keys.Hint, greyed out and marked when RBAC denies action.
+func (m *Model) actionHint(action Action, label string) string {
+	hint := m.Keys.Hint(action, label)
+	if hint == "" || m.denied(action) == "" {
+		return hint
+	}
+	return DisabledStyle.Render(hint + " (not allowed)")
+}
+
+// accessNote explains the denied actions among actions for the footer, or
+// returns "" if none is denied.
+func (m *Model) accessNote(actions ...Action) string {
+	var reasons []string
+	for _, action := range actions {
+		if m.Keys.Disabled(action) {
+			continue
+		}
+		if why := m.denied(action); why != "" {
+			reasons = append(reasons, why)
+		}
+	}
+	if len(reasons) == 0 {
+		return ""
+	}
+	return DisabledStyle.Render("Not allowed: " + strings.Join(reasons, "; ") + " (kubectl auth can-i)")
+}
@@ -0,0 +1,127 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"kubetbe/audit"
+	"kubetbe/msg"
+	"kubetbe/utils"
+)
+
+// auditLimit is how many of the newest audit entries the audit view shows.
+const auditLimit = 500
+
+// LoadAudit reads the newest audit log entries.
+func LoadAudit() tea.Cmd {
+	return func() tea.Msg {
+		entries, err := audit.Recent(auditLimit)
+		return msg.AuditLogMsg{Entries: entries, Err: err}
+	}
+}
+
+// keyView is the keymap view for the current screen. The audit log, the
+// context list and the namespace diagnosis sit over the main screens and
+// have their own bindings.
+func (m *Model) keyView() string {
+	if m.AuditPanel != nil {
+		return viewAudit
+	}
+	if m.ContextPicker != nil {
+		return viewContexts
+	}
+	if m.Diagnosis != nil {
+		return viewDiagnose
+	}
+	return m.State
+}
+
+// openAudit shows the audit view and loads its entries.
+func (m *Model) openAudit() tea.Cmd {
+	m.AuditPanel = &Panel{
+		Title:    "Audit log",
+		Content:  []string{"Loading audit log..."},
+		MaxLines: utils.Max(1, m.Height-10),
+	}
+	return LoadAudit()
+}
+
+// handleAuditKey handles action while the audit view is open.
+func (m *Model) handleAuditKey(action Action) tea.Cmd {
+	switch action {
+	case ActionAudit, ActionCancel:
+		m.AuditPanel = nil
+	case ActionRefresh:
+		return LoadAudit()
+	case ActionUp, ActionDown, ActionPageUp, ActionPageDown, ActionTop, ActionBottom:
+		scrollPanel(m.AuditPanel, action)
+	}
+	return nil
+}
+
+// auditLines renders entries as a table, newest first, with the stderr of
+// failed commands on the line below.
+func auditLines(entries []audit.Entry) []string {
+	if len(entries) == 0 {
+		return []string{"No mutating actions recorded yet."}
+	}
+	rows := [][]string{{"TIME", "USER", "CONTEXT", "NAMESPACE", "RESOURCE", "EXIT", "COMMAND"}}
+	for _, e := range entries {
+		rows = append(rows, []string{
+			e.Time.Local().Format("2006-01-02 15:04:05"),
+			utils.OrDash(e.User),
+			utils.OrDash(e.Context),
+			utils.OrDash(e.Namespace),
+			e.Resource,
+			fmt.Sprintf("%d", e.ExitCode),
+			e.Command,
+		})
+	}
+	table := strings.Split(strings.TrimSuffix(formatTable(rows), "\n"), "\n")
+
+	lines := []string{InfoStyle.Render(table[0])}
+	for i, e := range entries {
+		row := table[i+1]
+		if e.ExitCode != 0 {
+			lines = append(lines, ErrorStyle.Render(row))
+			detail := e.Stderr
+			if detail == "" {
+				detail = e.Error
+			}
+			for _, l := range strings.Split(detail, "\n") {
+				lines = append(lines, "    "+l)
+			}
+			continue
+		}
+		lines = append(lines, row)
+	}
+	return lines
+}
+
+// renderAudit shows the audit view full screen.
+func (m *Model) renderAudit() string {
+	var b strings.Builder
+	title := "Audit log - recent actions"
+	if path, err := audit.Path(); err == nil {
+		title += " (" + path + ")"
+	}
+	b.WriteString(TitleStyle.Render(title) + m.contextBadge() + m.connectionBadge() + m.readOnlyBadge())
+	b.WriteString("\n")
+
+	m.AuditPanel.MaxLines = utils.Max(1, m.Height-10)
+	b.WriteString(m.renderPanel(m.AuditPanel, true, m.Height-5, m.Width-2))
+	b.WriteString("\n")
+	b.WriteString(Footer(
+		m.contextHint(),
+		m.Keys.PairHint(ActionUp, ActionDown, "Scroll"),
+		m.Keys.PairHint(ActionPageUp, ActionPageDown, "Page"),
+		m.Keys.Hint(ActionRefresh, "Reload"),
+		m.Keys.Hint(ActionCancel, "Close"),
+		m.Keys.Hint(ActionHelp, "Help"),
+		m.Keys.Hint(ActionQuit, "Quit"),
+	))
+	return lipgloss.NewStyle().MaxHeight(m.Height).Render(b.String())
+}
@@ -0,0 +1,235 @@
+package ui
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"kubetbe/kubectl"
+	"kubetbe/msg"
+	"kubetbe/utils"
+)
+
+// Progress of one pod in a bulk delete.
+const (
+	bulkQueued   = "queued"
+	bulkDeleting = "deleting"
+	bulkDeleted  = "deleted"
+	bulkFailed   = "failed"
+)
+
+// bulkErrorLimit caps the failures listed under the pods panel; the rest
+// are in the audit log.
+const bulkErrorLimit = 3
+
+// BulkDelete tracks a delete of every marked pod.
+type BulkDelete struct {
+	Namespace string
+	Pods      []string          // in the order they were queued
+	Progress  map[string]string // pod -> bulkQueued, bulkDeleting, ...
+	Errs      map[string]error
+	Done      bool
+	updates   <-chan msg.BulkDeleteMsg
+}
+
+// counts returns how many pods are in each progress state.
+func (b *BulkDelete) counts() map[string]int {
+	counts := map[string]int{}
+	for _, state := range b.Progress {
+		counts[state]++
+	}
+	return counts
+}
+
+// markedPods returns the marked pods in name order.
+func (m *Model) markedPods() []string {
+	pods := make([]string, 0, len(m.MarkedPods))
+	for pod := range m.MarkedPods {
+		pods = append(pods, pod)
+	}
+	sort.Strings(pods)
+	return pods
+}
+
+// markPods marks every pod for which match returns true and reports how
+// many were newly marked.
+func (m *Model) markPods(match func(name, status string) bool) int {
+	if m.PodsPanel == nil {
+		return 0
+	}
+	added := 0
+	for name, status := range parsePodStatuses(m.PodsPanel.Content) {
+		if match(name, status) && !m.MarkedPods[name] {
+			m.MarkedPods[name] = true
+			added++
+		}
+	}
+	return added
+}
+
+// parsePodStatuses maps each pod in kubectl get pods output to its STATUS
+// column, e.g. Running, Completed or Evicted.
+func parsePodStatuses(content []string) map[string]string {
+	statuses := map[string]string{}
+	for _, line := range content {
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "NAME") {
+			continue
+		}
+		if fields := strings.Fields(line); len(fields) >= 3 {
+			statuses[fields[0]] = fields[2]
+		}
+	}
+	return statuses
+}
+
+// podNameMatches matches a pod name against a glob pattern, or against a
+// plain substring when pattern has no glob characters.
+func podNameMatches(pattern, name string) bool {
+	if strings.ContainsAny(pattern, "*?[") {
+		ok, _ := path.Match(pattern, name)
+		return ok
+	}
+	return strings.Contains(name, pattern)
+}
+
+// handlePodPatternKey processes a key while the mark-by-pattern prompt is
+// open.
+func (m *Model) handlePodPatternKey(msg tea.KeyMsg) bool {
+	switch msg.Type {
+	case tea.KeyEnter:
+		pattern := strings.TrimSpace(m.PodPattern)
+		m.PodPatternActive = false
+		if pattern == "" {
+			return true
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			m.Err = fmt.Errorf("invalid pattern %q: %v", pattern, err)
+			return true
+		}
+		if m.markPods(func(name, _ string) bool { return podNameMatches(pattern, name) }) == 0 {
+			m.Err = fmt.Errorf("no unmarked pods match %q", pattern)
+		}
+	case tea.KeyEscape:
+		m.PodPatternActive = false
+	case tea.KeyBackspace, tea.KeyDelete:
+		if len(m.PodPattern) > 0 {
+			runes := []rune(m.PodPattern)
+			m.PodPattern = string(runes[:len(runes)-1])
+		}
+	case tea.KeyCtrlU:
+		m.PodPattern = ""
+	case tea.KeyRunes, tea.KeySpace:
+		m.PodPattern += string(msg.Runes)
+	default:
+		return false
+	}
+	return true
+}
+
+// startBulkDelete removes pods in namespace with opts.
+func (m *Model) startBulkDelete(namespace string, pods []string, opts kubectl.DeleteOptions) tea.Cmd {
+	b := &BulkDelete{
+		Namespace: namespace,
+		Pods:      pods,
+		Progress:  map[string]string{},
+		Errs:      map[string]error{},
+	}
+	for _, pod := range pods {
+		b.Progress[pod] = bulkQueued
+	}
+	updates, cmd := kubectl.DeletePods(m.sessionContext(), m.kubeFlags(), namespace, pods, m.Config.BulkDeleteWorkers, opts)
+	b.updates = updates
+	m.BulkDelete = b
+	m.BulkDeleteConfirm = false
+	return cmd
+}
+
+// bulkDeleteRunning reports whether a bulk delete is still in progress.
+func (m *Model) bulkDeleteRunning() bool {
+	return m.BulkDelete != nil && !m.BulkDelete.Done
+}
+
+// podMarker is appended to a pod's line in the pods panel to show it is
+// marked or how its bulk delete is going.
+func (m *Model) podMarker(pod string) string {
+	if b := m.BulkDelete; b != nil && b.Namespace == m.SelectedNS {
+		switch b.Progress[pod] {
+		case bulkQueued:
+			return "  ○ queued"
+		case bulkDeleting:
+			return "  … deleting"
+		case bulkDeleted:
+			return "  ✓ deleted"
+		case bulkFailed:
+			return "  ✗ failed"
+		}
+	}
+	if m.MarkedPods[pod] {
+		return "  ● marked"
+	}
+	return ""
+}
+
+// markedPodLines returns the pods panel content with markers appended.
+func (m *Model) markedPodLines(content []string) []string {
+	lines := make([]string, len(content))
+	for i, line := range content {
+		lines[i] = line
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "NAME") {
+			continue
+		}
+		if fields := strings.Fields(line); len(fields) > 0 {
+			lines[i] += m.podMarker(fields[0])
+		}
+	}
+	return lines
+}
+
+// renderBulkStatus renders the mark count, the bulk delete confirmation,
+// or the progress and error summary of a bulk delete.
+func (m *Model) renderBulkStatus() string {
+	if m.PodPatternActive {
+		return SelectedStyle.Render("Mark pods matching: "+m.PodPattern+"_") +
+			"  Enter: Mark, Esc: Cancel (glob such as web-*, or any part of the name)"
+	}
+
+	if b := m.BulkDelete; b != nil {
+		counts := b.counts()
+		if !b.Done {
+			return InfoStyle.Render(fmt.Sprintf("Deleting %d pods: %d deleted, %d failed, %d running, %d queued",
+				len(b.Pods), counts[bulkDeleted], counts[bulkFailed], counts[bulkDeleting], counts[bulkQueued]))
+		}
+		lines := []string{InfoStyle.Render(fmt.Sprintf("Deleted %d of %d pods", counts[bulkDeleted], len(b.Pods)))}
+		if counts[bulkFailed] > 0 {
+			lines[0] += ErrorStyle.Render(fmt.Sprintf(", %d failed:", counts[bulkFailed]))
+			shown := 0
+			for _, pod := range b.Pods {
+				err, failed := b.Errs[pod]
+				if !failed {
+					continue
+				}
+				if shown == bulkErrorLimit {
+					lines = append(lines, fmt.Sprintf("  ... and %d more (%s: audit log)", counts[bulkFailed]-shown, m.keyFor(ActionAudit)))
+					break
+				}
+				lines = append(lines, fmt.Sprintf("  %s: %s", pod, utils.FirstLine(err.Error())))
+				shown++
+			}
+		}
+		lines[len(lines)-1] += "  " + m.Keys.Hint(ActionCancel, "Dismiss")
+		return strings.Join(lines, "\n")
+	}
+
+	if m.BulkDeleteConfirm {
+		return ErrorStyle.Render(fmt.Sprintf("⚠️  Delete %d marked pods? Press '%s' again to confirm, any other key to cancel",
+			len(m.MarkedPods), m.keyFor(ActionDelete)))
+	}
+	if len(m.MarkedPods) > 0 {
+		return InfoStyle.Render(fmt.Sprintf("%d pods marked", len(m.MarkedPods))) + "  " +
+			Footer(m.actionHint(ActionDelete, "Delete marked"), m.Keys.Hint(ActionCancel, "Clear marks"))
+	}
+	return ""
+}
@@ -0,0 +1,120 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"kubetbe/kubectl"
+	"kubetbe/msg"
+)
+
+// multiCluster reports whether the namespace list merges several contexts.
+func (m *Model) multiCluster() bool {
+	return len(m.MultiContexts) > 0
+}
+
+// fetchNamespaces lists namespaces from the current context, or from every
+// context in multi-cluster mode.
+func (m *Model) fetchNamespaces() tea.Cmd {
+	m.NamespacesFetching = true
+	if m.multiCluster() {
+		return kubectl.FetchNamespacesMulti(m.viewContext(), m.kubeFlags(), m.MultiContexts)
+	}
+	return kubectl.FetchNamespaces(m.viewContext(), m.kubeFlags())
+}
+
+// findResource runs a lookup in the current context, or in every context in
+// multi-cluster mode.
+func (m *Model) findResource(query string) tea.Cmd {
+	if m.multiCluster() {
+		return kubectl.FindResourceMulti(m.viewContext(), m.kubeFlags(), m.MultiContexts, query)
+	}
+	return kubectl.FindResource(m.viewContext(), m.kubeFlags(), query)
+}
+
+// namespaceFlags are the kubectl flags for calls about ns, which reach the
+// cluster it was listed from without making that cluster the current one.
+func (m *Model) namespaceFlags(ns msg.NamespaceInfo) kubectl.Flags {
+	f := m.kubeFlags()
+	if ns.Context != "" {
+		f.Context = ns.Context
+	}
+	return f
+}
+
+// keepStaleClusters merges list with the namespaces a degraded cluster
+// returned last time, so one failing cluster does not empty its part of the
+// list. It must run before list replaces m.AllNamespaces.
+func (m *Model) keepStaleClusters(list NamespaceListMsg) []msg.NamespaceInfo {
+	if m.ClustersUpdated == nil {
+		m.ClustersUpdated = map[string]time.Time{}
+	}
+	merged := list.Namespaces
+	order := map[string]int{}
+	stale := false
+	for i, c := range list.Clusters {
+		order[c.Context] = i
+		if c.Err == nil {
+			m.ClustersUpdated[c.Context] = time.Now()
+			continue
+		}
+		for _, ns := range m.AllNamespaces {
+			if ns.Context == c.Context {
+				merged = append(merged, ns)
+				stale = true
+			}
+		}
+	}
+	if stale {
+		sort.SliceStable(merged, func(i, j int) bool {
+			if merged[i].Name != merged[j].Name {
+				return merged[i].Name < merged[j].Name
+			}
+			return order[merged[i].Context] < order[merged[j].Context]
+		})
+	}
+	return merged
+}
+
+// withCluster inserts the CLUSTER column after NAME in multi-cluster mode.
+func (m *Model) withCluster(cols []string, cluster string) []string {
+	if !m.multiCluster() {
+		return cols
+	}
+	out := append([]string{}, cols[:2]...)
+	out = append(out, cluster)
+	return append(out, cols[2:]...)
+}
+
+// renderClusterStatus summarizes each cluster of a multi-cluster list. A
+// degraded cluster gets its error, so the missing or stale namespaces are
+// explained.
+func (m *Model) renderClusterStatus() string {
+	if !m.multiCluster() || len(m.Clusters) == 0 {
+		return ""
+	}
+	parts := make([]string, len(m.Clusters))
+	var problems []string
+	for i, c := range m.Clusters {
+		if c.Err != nil {
+			parts[i] = ErrorStyle.Render(fmt.Sprintf("✗ %s degraded", c.Context)) + staleMarker(m.ClustersUpdated[c.Context])
+			problems = append(problems, ErrorStyle.Render(fmt.Sprintf("⚠️  %s: %s", c.Context, errorSummary(c.Err))))
+			if m.ErrorDetails {
+				for _, line := range strings.Split(kubectl.Explain(c.Err).Raw, "\n") {
+					problems = append(problems, "    "+line)
+				}
+			}
+			continue
+		}
+		parts[i] = fmt.Sprintf("✓ %s (%d)", c.Context, c.Namespaces)
+	}
+	if hint := m.errorDetailsHint(); hint != "" && len(problems) > 0 {
+		problems = append(problems, "  "+hint)
+	}
+	lines := append([]string{"Clusters: " + strings.Join(parts, "  ")}, problems...)
+	return strings.Join(lines, "\n") + "\n"
+}
@@ -0,0 +1,119 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"kubetbe/utils"
+)
+
+// TypedConfirm guards a destructive action, such as a delete in a protected
+// namespace: it only runs once the user has typed the full name of what it
+// acts on.
+type TypedConfirm struct {
+	What     string // e.g. "namespace" or "pod", for the prompt
+	Expected string // the name that must be typed
+	Reason   string // why confirmation is needed; "is protected" if empty
+	Verb     string // what typing the name does; "delete it" if empty
+	Input    string
+	Mismatch bool // last Enter did not match
+	Run      func() tea.Cmd
+}
+
+// handleTypedConfirmKey processes a key while a typed confirmation is open.
+func (m *Model) handleTypedConfirmKey(msg tea.KeyMsg) tea.Cmd {
+	c := m.TypedConfirm
+	switch msg.Type {
+	case tea.KeyEnter:
+		if c.Input != c.Expected {
+			c.Mismatch = true
+			c.Input = ""
+			return nil
+		}
+		m.TypedConfirm = nil
+		return c.Run()
+	case tea.KeyEscape:
+		m.TypedConfirm = nil
+	case tea.KeyBackspace, tea.KeyDelete:
+		if len(c.Input) > 0 {
+			runes := []rune(c.Input)
+			c.Input = string(runes[:len(runes)-1])
+		}
+	case tea.KeyCtrlU:
+		c.Input = ""
+	case tea.KeyRunes:
+		c.Input += string(msg.Runes)
+		c.Mismatch = false
+	}
+	return nil
+}
+
+// renderTypedConfirm renders the prompt for an open typed confirmation.
+func (m *Model) renderTypedConfirm() string {
+	c := m.TypedConfirm
+	verb := c.Verb
+	if verb == "" {
+		verb = "delete it"
+	}
+	reason := c.Reason
+	if reason == "" {
+		reason = "is protected"
+	}
+	s := ErrorStyle.Render(fmt.Sprintf("⚠️  %s '%s' %s. Type its full name to %s:", c.What, c.Expected, reason, verb))
+	s += "\n" + SelectedStyle.Render(c.Input+"_")
+	if c.Mismatch {
+		s += "  " + ErrorStyle.Render("name does not match")
+	}
+	s += "\nEnter: Confirm, Esc: Cancel"
+	return s
+}
+
+// previewKindLimit caps how many kinds a delete preview lists, so a busy
+// namespace does not push the namespace table off screen.
+const previewKindLimit = 8
+
+// renderDeletePreview shows what deleting namespace would remove, from the
+// server-side dry run, or "" if no preview for it is pending.
+func (m *Model) renderDeletePreview(namespace string) string {
+	p := m.DeletePreview
+	if p == nil || p.Namespace != namespace {
+		return ""
+	}
+	if m.DeletePreviewLoading {
+		return InfoStyle.Render("Checking what would be deleted (server dry run)...")
+	}
+	if p.Err != nil {
+		return m.renderError(p.Err)
+	}
+
+	var lines []string
+	lines = append(lines, InfoStyle.Render("Dry run: "+p.DryRun))
+	total := 0
+	for _, g := range p.Resources {
+		total += len(g.Names)
+	}
+	if total == 0 {
+		lines = append(lines, "No namespaced resources found; only the namespace itself will go.")
+	} else {
+		lines = append(lines, fmt.Sprintf("Will also remove %d resources:", total))
+		width := utils.Max(20, m.Width-8)
+		for i, g := range p.Resources {
+			if i == previewKindLimit {
+				lines = append(lines, fmt.Sprintf("  ... and %d more kinds", len(p.Resources)-i))
+				break
+			}
+			line := fmt.Sprintf("  %-32s %4d  %s", g.Kind, len(g.Names), strings.Join(g.Names, ", "))
+			if lipgloss.Width(line) > width {
+				line = string([]rune(line)[:width-1]) + "…"
+			}
+			lines = append(lines, line)
+		}
+	}
+	if p.ListErr != nil {
+		lines = append(lines, ErrorStyle.Render(fmt.Sprintf("Incomplete: %v", p.ListErr)))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"kubetbe/kubectl"
+)
+
+// When a refresh fails kubetbe keeps showing what it last read, marked with
+// the time of that read, rather than emptying the screen. The header badge
+// says how well the cluster is being reached, from the reads kubectl made.
+
+// staleMarker is " (stale since HH:MM:SS)" for data last read at updated,
+// or "" if it was never read.
+func staleMarker(updated time.Time) string {
+	if updated.IsZero() {
+		return ""
+	}
+	return " (stale since " + updated.Format("15:04:05") + ")"
+}
+
+// panelStale marks the title of a panel whose last refresh failed.
+func panelStale(p *Panel) string {
+	if p == nil || p.Err == nil {
+		return ""
+	}
+	return staleMarker(p.Updated)
+}
+
+// namespacesStale marks the namespace list when its last refresh failed.
+func (m *Model) namespacesStale() string {
+	if m.NamespacesErr == nil {
+		return ""
+	}
+	return staleMarker(m.NamespacesUpdated)
+}
+
+// connection is the state of the cluster being browsed. In multi-cluster
+// mode it is the worst state of all the clusters, with the slowest latency.
+func (m *Model) connection() kubectl.Connection {
+	if !m.multiCluster() {
+		return kubectl.ConnectionTo(m.KubeContext)
+	}
+	var worst kubectl.Connection
+	for _, name := range m.MultiContexts {
+		c := kubectl.ConnectionTo(name)
+		if c.State > worst.State {
+			worst.State = c.State
+		}
+		worst.Latency = max(worst.Latency, c.Latency)
+		worst.Failures = max(worst.Failures, c.Failures)
+	}
+	return worst
+}
+
+// connectionBadge renders the connection state for titles. The symbol
+// differs per state, so it reads without color too.
+func (m *Model) connectionBadge() string {
+	c := m.connection()
+	switch c.State {
+	case kubectl.ConnConnected:
+		return " " + InfoStyle.Render("● connected "+formatLatency(c.Latency))
+	case kubectl.ConnDegraded:
+		text := "◐ degraded"
+		if c.Failures == 0 {
+			text += " " + formatLatency(c.Latency)
+		}
+		return " " + TerminatingStyle.Copy().Padding(0).Render(text)
+	case kubectl.ConnOffline:
+		return " " + ErrorStyle.Render("○ offline")
+	}
+	return ""
+}
+
+// formatLatency shows short latencies in milliseconds and long ones in
+// seconds.
+func formatLatency(d time.Duration) string {
+	if d < time.Second {
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	}
+	return fmt.Sprintf("%.1fs", d.Seconds())
+}
@@ -0,0 +1,255 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"kubetbe/kubectl"
+	"kubetbe/msg"
+	"kubetbe/utils"
+)
+
+// ContextPicker lists the kubeconfig's contexts to switch between.
+type ContextPicker struct {
+	Contexts []msg.KubeContext
+	Cursor   int
+	Loading  bool
+	Err      error
+	Refused  string // why the last switch was not made
+	Panel    *Panel
+}
+
+// contextName is the kube context kubectl calls go to, or "" before the
+// kubeconfig has been read.
+func (m *Model) contextName() string {
+	if m.KubeContext != "" {
+		return m.KubeContext
+	}
+	return m.DefaultContext
+}
+
+// activeContext is the context kubectl calls go to: the open namespace's
+// cluster in multi-cluster mode, KubeContext otherwise.
+func (m *Model) activeContext() string {
+	if m.NamespaceContext != "" {
+		return m.NamespaceContext
+	}
+	return m.KubeContext
+}
+
+// contextLabel names the context the current screen shows: all of them on
+// a multi-cluster namespace list, the open namespace's in a multi-cluster
+// panel view.
+func (m *Model) contextLabel() string {
+	if m.multiCluster() && m.State == "namespace_select" && m.Diagnosis == nil {
+		return strings.Join(m.MultiContexts, ", ")
+	}
+	if m.NamespaceContext != "" {
+		return m.NamespaceContext
+	}
+	return m.contextName()
+}
+
+// contextBadge names the active context next to screen titles.
+func (m *Model) contextBadge() string {
+	name := m.contextLabel()
+	if name == "" {
+		return ""
+	}
+	return " " + ContextStyle.Render("⎈ "+name)
+}
+
+// kubeFlags are the kubectl flags for calls to the active context.
+func (m *Model) kubeFlags() kubectl.Flags {
+	f := kubectl.CurrentFlags()
+	f.Context = m.activeContext()
+	return f
+}
+
+// contextHint names the active context in footers, with the key to switch
+// where switching is possible.
+func (m *Model) contextHint() string {
+	name := m.contextLabel()
+	if name == "" {
+		name = "current"
+	}
+	if m.Keys.Bound(m.keyView(), ActionContext) {
+		return m.Keys.Hint(ActionContext, "Context ("+name+")")
+	}
+	return "Context: " + name
+}
+
+// openContextPicker shows the context list and loads it.
+func (m *Model) openContextPicker() tea.Cmd {
+	m.ContextPicker = &ContextPicker{
+		Loading: true,
+		Panel:   &Panel{Title: "Contexts"},
+	}
+	return kubectl.FetchContexts(m.sessionContext(), m.kubeFlags())
+}
+
+// handleContextKey handles action while the context list is open.
+func (m *Model) handleContextKey(action Action) tea.Cmd {
+	p := m.ContextPicker
+	last := len(p.Contexts) - 1
+	switch action {
+	case ActionCancel, ActionContext:
+		m.ContextPicker = nil
+	case ActionRefresh:
+		p.Loading = true
+		return kubectl.FetchContexts(m.sessionContext(), m.kubeFlags())
+	case ActionUp:
+		p.Cursor = utils.Max(0, p.Cursor-1)
+	case ActionDown:
+		p.Cursor = utils.Max(0, utils.Min(last, p.Cursor+1))
+	case ActionPageUp:
+		p.Cursor = utils.Max(0, p.Cursor-p.Panel.MaxLines)
+	case ActionPageDown:
+		p.Cursor = utils.Max(0, utils.Min(last, p.Cursor+p.Panel.MaxLines))
+	case ActionTop:
+		p.Cursor = 0
+	case ActionBottom:
+		p.Cursor = utils.Max(0, last)
+	case ActionOpen:
+		if m.bulkDeleteRunning() {
+			// Its progress lives in the namespace view that switching leaves
+			p.Refused = fmt.Sprintf("A bulk delete in '%s' is still running; switch once it has finished.", m.BulkDelete.Namespace)
+			break
+		}
+		if p.Cursor <= last {
+			m.ContextPicker = nil
+			return m.switchContext(p.Contexts[p.Cursor].Name)
+		}
+	}
+	return nil
+}
+
+// applyContexts stores a context listing. The kubeconfig's current context
+// is remembered even when the list is not open, for titles and footers.
+func (m *Model) applyContexts(list msg.ContextListMsg) {
+	if list.Err == nil {
+		m.DefaultContext = list.Current
+	}
+	p := m.ContextPicker
+	if p == nil {
+		return
+	}
+	p.Loading = false
+	p.Err = list.Err
+	p.Contexts = list.Contexts
+	p.Cursor = 0
+	for i, c := range p.Contexts {
+		if c.Name == m.contextName() {
+			p.Cursor = i
+			break
+		}
+	}
+}
+
+// switchContext points every following kubectl call at the named context
+// and starts over from its namespace list. The kubeconfig is left alone.
+func (m *Model) switchContext(name string) tea.Cmd {
+	if name == m.contextName() && !m.multiCluster() {
+		return nil
+	}
+	// Nothing the old context's view started is wanted any more
+	m.leaveView()
+	m.KubeContext = name
+	m.NamespaceContext = ""
+	// Picking one context leaves multi-cluster mode
+	m.MultiContexts = nil
+	m.Clusters = nil
+	m.ClustersUpdated = nil
+
+	m.Err = nil
+	m.NamespacesErr = nil
+	m.NamespacesUpdated = time.Time{}
+	m.AllNamespaces = []msg.NamespaceInfo{}
+	m.Namespaces = []msg.NamespaceInfo{}
+	m.Cursor = 0
+	m.NSCurrentPage = 0
+	m.DeleteConfirmation = ""
+	m.DeletePreview = nil
+	m.DeletePreviewLoading = false
+	m.TypedConfirm = nil
+	m.Diagnosis = nil
+	m.ServiceIPResult = nil
+	m.ServiceIPErr = nil
+	m.ServiceIPListErr = nil
+	m.ServiceIPCursor = 0
+
+	if m.State == "panel_view" {
+		return m.closeNamespace()
+	}
+	return kubectl.FetchNamespaces(m.viewContext(), m.kubeFlags())
+}
+
+// renderContextPicker shows the context list full screen.
+func (m *Model) renderContextPicker() string {
+	p := m.ContextPicker
+	var b strings.Builder
+	b.WriteString(TitleStyle.Render("Kube contexts") + m.contextBadge() + m.connectionBadge() + m.readOnlyBadge())
+	b.WriteString("\n")
+
+	var lines []string
+	selected := -1
+	switch {
+	case p.Loading && len(p.Contexts) == 0:
+		lines = []string{"Loading contexts..."}
+	case p.Err != nil:
+		lines = m.errorLines(p.Err)
+	case len(p.Contexts) == 0:
+		lines = []string{"No contexts in the kubeconfig."}
+	default:
+		rows := [][]string{{" ", "NAME", "CLUSTER", "USER", "NAMESPACE"}}
+		for _, c := range p.Contexts {
+			marker := " "
+			if c.Name == m.contextName() {
+				marker = "*"
+			}
+			rows = append(rows, []string{marker, c.Name, utils.OrDash(c.Cluster), utils.OrDash(c.User), utils.OrDash(c.Namespace)})
+		}
+		table := strings.Split(strings.TrimSuffix(formatTable(rows), "\n"), "\n")
+		// Rows are padded by their style; pad the header to match
+		lines = append(lines, InfoStyle.Render(" "+table[0]))
+		for i, row := range table[1:] {
+			if i == p.Cursor {
+				selected = len(lines)
+				row = SelectedStyle.Render(row)
+			} else {
+				row = NormalStyle.Render(row)
+			}
+			lines = append(lines, row)
+		}
+	}
+
+	panel := p.Panel
+	panel.Content = lines
+	panel.MaxLines = utils.Max(1, m.Height-10)
+	// Keep the selected context on screen
+	if selected >= 0 {
+		if selected < panel.ScrollPos {
+			panel.ScrollPos = selected
+		} else if selected >= panel.ScrollPos+panel.MaxLines {
+			panel.ScrollPos = selected - panel.MaxLines + 1
+		}
+	}
+	b.WriteString(m.renderPanel(panel, true, m.Height-5, m.Width-2))
+	b.WriteString("\n")
+	b.WriteString(Footer(
+		m.contextHint(),
+		m.Keys.PairHint(ActionUp, ActionDown, "Select"),
+		m.Keys.Hint(ActionOpen, "Switch"),
+		m.Keys.Hint(ActionRefresh, "Reload"),
+		m.Keys.Hint(ActionCancel, "Close"),
+		m.Keys.Hint(ActionQuit, "Quit"),
+	))
+	if p.Refused != "" {
+		b.WriteString("\n" + ErrorStyle.Render(p.Refused))
+	}
+	return lipgloss.NewStyle().MaxHeight(m.Height).Render(b.String())
+}
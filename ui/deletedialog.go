@@ -0,0 +1,240 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"kubetbe/kubectl"
+	"kubetbe/msg"
+)
+
+// Fields of the delete options dialog, top to bottom.
+const (
+	dialogMethod = iota
+	dialogGrace
+	dialogForce
+	dialogFields
+)
+
+// maxGracePeriod is the longest grace period the dialog accepts, in seconds.
+const maxGracePeriod = 86400
+
+// DeleteDialog collects options for removing the highlighted pod or every
+// marked pod, and warns when PodDisruptionBudgets would be violated.
+type DeleteDialog struct {
+	Namespace  string
+	Pods       []string
+	Field      int
+	Evict      bool
+	Force      bool
+	Grace      string // seconds as typed; empty keeps the pod's own grace period
+	Checking   bool   // PodDisruptionBudget check still running
+	Violations []msg.DisruptionViolation
+	CheckErr   error
+	Err        error // problem with the last Enter
+	Overridden bool  // Enter pressed once despite the budget warning
+}
+
+// options turns the dialog fields into kubectl delete options.
+func (d *DeleteDialog) options() (kubectl.DeleteOptions, error) {
+	opts := kubectl.DeleteOptions{Evict: d.Evict, Force: d.Force && !d.Evict}
+	if d.Grace != "" && !opts.Force {
+		seconds, err := strconv.Atoi(d.Grace)
+		if err != nil || seconds < 1 || seconds > maxGracePeriod {
+			return opts, fmt.Errorf("grace period must be between 1 and %d seconds", maxGracePeriod)
+		}
+		opts.GracePeriod = seconds
+	}
+	return opts, nil
+}
+
+// openDeleteDialog opens the dialog for the marked pods, or the highlighted
+// pod if none are marked, and starts the PodDisruptionBudget check.
+func (m *Model) openDeleteDialog() tea.Cmd {
+	pods := m.markedPods()
+	if len(pods) == 0 {
+		selectedPod, _ := m.selectedPodAndList()
+		if selectedPod == "" {
+			return nil
+		}
+		pods = []string{selectedPod}
+	}
+	m.PodDeleteConfirmation = ""
+	m.BulkDeleteConfirm = false
+	m.DeleteDialog = &DeleteDialog{Namespace: m.SelectedNS, Pods: pods, Checking: true}
+	// Offer the method RBAC allows when it only allows evicting
+	if !kubectl.AccessIn(m.activeContext(), m.SelectedNS).Allows(kubectl.CanDeletePods) {
+		m.DeleteDialog.Evict = true
+	}
+	return kubectl.CheckDisruption(m.viewContext(), m.kubeFlags(), m.SelectedNS, pods)
+}
+
+// handleDeleteDialogKey processes a key while the delete dialog is open.
+func (m *Model) handleDeleteDialogKey(msg tea.KeyMsg) tea.Cmd {
+	d := m.DeleteDialog
+	d.Err = nil
+	switch msg.Type {
+	case tea.KeyEscape:
+		m.DeleteDialog = nil
+	case tea.KeyEnter:
+		return m.confirmDeleteDialog()
+	case tea.KeyUp, tea.KeyShiftTab:
+		d.Field = (d.Field + dialogFields - 1) % dialogFields
+	case tea.KeyDown, tea.KeyTab:
+		d.Field = (d.Field + 1) % dialogFields
+	case tea.KeyLeft, tea.KeyRight, tea.KeySpace:
+		switch d.Field {
+		case dialogMethod:
+			d.Evict = !d.Evict
+			d.Overridden = false
+		case dialogForce:
+			d.Force = !d.Force
+		}
+	case tea.KeyBackspace, tea.KeyDelete:
+		if d.Field == dialogGrace && len(d.Grace) > 0 {
+			d.Grace = d.Grace[:len(d.Grace)-1]
+		}
+	case tea.KeyRunes:
+		if d.Field == dialogGrace {
+			for _, r := range msg.Runes {
+				if r >= '0' && r <= '9' && len(d.Grace) < 6 {
+					d.Grace += string(r)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// confirmDeleteDialog validates the dialog and starts the delete, going
+// through the typed confirmation first in protected namespaces.
+func (m *Model) confirmDeleteDialog() tea.Cmd {
+	d := m.DeleteDialog
+	opts, err := d.options()
+	if err != nil {
+		d.Err = err
+		return nil
+	}
+	if d.Checking {
+		d.Err = fmt.Errorf("still checking PodDisruptionBudgets, press Enter again in a moment")
+		return nil
+	}
+	if len(d.Violations) > 0 && !opts.Evict && !d.Overridden {
+		// A plain delete bypasses the budget; make that a deliberate choice
+		d.Overridden = true
+		return nil
+	}
+	m.DeleteDialog = nil
+
+	namespace, pods := d.Namespace, d.Pods
+	run := func() tea.Cmd {
+		if len(pods) == 1 {
+			m.DeletingPod = pods[0]
+			return kubectl.DeletePod(m.sessionContext(), m.kubeFlags(), namespace, pods[0], opts)
+		}
+		return m.startBulkDelete(namespace, pods, opts)
+	}
+	if !m.Config.IsProtected(namespace) {
+		return run()
+	}
+	if len(pods) == 1 {
+		m.TypedConfirm = &TypedConfirm{
+			What:     fmt.Sprintf("Pod in namespace '%s'", namespace),
+			Expected: pods[0],
+			Verb:     opts.Verb() + " it",
+			Run:      run,
+		}
+	} else {
+		m.TypedConfirm = &TypedConfirm{
+			What:     "Namespace",
+			Expected: namespace,
+			Verb:     fmt.Sprintf("%s %d pods in it", opts.Verb(), len(pods)),
+			Run:      run,
+		}
+	}
+	return nil
+}
+
+// renderDeleteDialog shows the delete options dialog centered on screen.
+func (m *Model) renderDeleteDialog() string {
+	d := m.DeleteDialog
+	var b strings.Builder
+
+	what := fmt.Sprintf("pod '%s'", d.Pods[0])
+	if len(d.Pods) > 1 {
+		what = fmt.Sprintf("%d marked pods", len(d.Pods))
+	}
+	b.WriteString(TitleStyle.Render(fmt.Sprintf("Remove %s in %s", what, d.Namespace)))
+	b.WriteString("\n\n")
+
+	field := func(i int, label, value string) {
+		line := fmt.Sprintf("%-14s %s", label, value)
+		if d.Field == i {
+			b.WriteString(SelectedStyle.Render(line))
+		} else {
+			b.WriteString(NormalStyle.Render(line))
+		}
+		b.WriteString("\n")
+	}
+	method := "[x] delete   [ ] evict"
+	if d.Evict {
+		method = "[ ] delete   [x] evict (Eviction API, honors PodDisruptionBudgets)"
+	}
+	field(dialogMethod, "Method", method)
+	grace := d.Grace + "_"
+	if d.Grace == "" && d.Field != dialogGrace {
+		grace = "pod default"
+	}
+	if d.Force && !d.Evict {
+		grace = "0 (forced)"
+	}
+	field(dialogGrace, "Grace period", grace)
+	force := "[ ]"
+	if d.Force {
+		force = "[x]"
+	}
+	if d.Evict {
+		force += " (delete only)"
+	} else {
+		force += " skip waiting for the kubelet; for pods stuck Terminating"
+	}
+	field(dialogForce, "Force", force)
+	b.WriteString("\n")
+
+	switch {
+	case d.Checking:
+		b.WriteString(InfoStyle.Render("Checking PodDisruptionBudgets..."))
+		b.WriteString("\n")
+	case d.CheckErr != nil:
+		b.WriteString(ErrorStyle.Render(fmt.Sprintf("Could not check PodDisruptionBudgets: %v", d.CheckErr)))
+		b.WriteString("\n")
+	case len(d.Violations) == 0:
+		b.WriteString(InfoStyle.Render("No PodDisruptionBudget is violated."))
+		b.WriteString("\n")
+	default:
+		for _, v := range d.Violations {
+			b.WriteString(ErrorStyle.Render(fmt.Sprintf("⚠️  PodDisruptionBudget '%s' allows %d disruptions; removing %s would violate it",
+				v.Budget, v.Allowed, strings.Join(v.Pods, ", "))))
+			b.WriteString("\n")
+		}
+		if d.Evict {
+			b.WriteString("The API server will refuse evictions that violate a budget.\n")
+		} else if d.Overridden {
+			b.WriteString(ErrorStyle.Render("A delete ignores the budget. Press Enter again to delete anyway."))
+			b.WriteString("\n")
+		} else {
+			b.WriteString("A delete ignores the budget; choose evict to respect it.\n")
+		}
+	}
+	if d.Err != nil {
+		b.WriteString(ErrorStyle.Render(d.Err.Error()))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n↑/↓: Field, Space: Toggle, 0-9: Grace seconds, Enter: " + kubectl.DeleteOptions{Evict: d.Evict}.Verb() + ", Esc: Cancel")
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, PanelStyle.Render(b.String()))
+}
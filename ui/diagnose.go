@@ -0,0 +1,225 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"kubetbe/kubectl"
+	"kubetbe/msg"
+	"kubetbe/utils"
+)
+
+// Diagnosis is the view explaining why a namespace is stuck Terminating.
+type Diagnosis struct {
+	Namespace string
+	Result    *msg.NamespaceDiagnosisMsg // nil while loading
+	Cursor    int                        // selected entry of Result.Resources
+	Panel     *Panel
+	Status    string // outcome of the last action
+	StatusErr bool
+	flags     kubectl.Flags // reach the cluster the namespace is in
+}
+
+// openDiagnosis shows the diagnosis view for ns.
+func (m *Model) openDiagnosis(ns msg.NamespaceInfo) tea.Cmd {
+	m.DeleteConfirmation = ""
+	m.Diagnosis = &Diagnosis{
+		Namespace: ns.Name,
+		Panel:     &Panel{Title: "Diagnosis", Content: []string{"Diagnosing..."}},
+		flags:     m.namespaceFlags(ns),
+	}
+	return kubectl.DiagnoseNamespace(m.viewContext(), m.Diagnosis.flags, ns.Name)
+}
+
+// handleDiagnosisKey handles action while the diagnosis view is open.
+func (m *Model) handleDiagnosisKey(action Action) tea.Cmd {
+	d := m.Diagnosis
+	resources := 0
+	if d.Result != nil {
+		resources = len(d.Result.Resources)
+	}
+	switch action {
+	case ActionCancel, ActionBack, ActionDiagnose:
+		m.Diagnosis = nil
+	case ActionRefresh:
+		return kubectl.DiagnoseNamespace(m.viewContext(), d.flags, d.Namespace)
+	case ActionUp:
+		d.Cursor = utils.Max(0, d.Cursor-1)
+	case ActionDown:
+		d.Cursor = utils.Max(0, utils.Min(resources-1, d.Cursor+1))
+	case ActionPageUp:
+		d.Cursor = utils.Max(0, d.Cursor-d.Panel.MaxLines)
+	case ActionPageDown:
+		d.Cursor = utils.Max(0, utils.Min(resources-1, d.Cursor+d.Panel.MaxLines))
+	case ActionTop:
+		d.Cursor = 0
+	case ActionBottom:
+		d.Cursor = utils.Max(0, resources-1)
+	case ActionClearFinal:
+		if m.Keys.Disabled(action) {
+			d.Status, d.StatusErr = fmt.Sprintf("%s is disabled in read-only mode", action), true
+			break
+		}
+		if resources == 0 {
+			break
+		}
+		r := d.Result.Resources[d.Cursor]
+		if len(r.Finalizers) == 0 {
+			d.Status, d.StatusErr = fmt.Sprintf("%s has no finalizers", r.Ref), true
+			break
+		}
+		namespace, ref, f := d.Namespace, r.Ref, d.flags
+		m.TypedConfirm = &TypedConfirm{
+			What:     "Resource",
+			Expected: ref,
+			Reason:   fmt.Sprintf("waits on %s; clearing skips that cleanup", strings.Join(r.Finalizers, ", ")),
+			Verb:     "clear its finalizers",
+			Run: func() tea.Cmd {
+				d.Status, d.StatusErr = fmt.Sprintf("Clearing finalizers on %s...", ref), false
+				return kubectl.ClearFinalizers(m.sessionContext(), f, namespace, ref)
+			},
+		}
+	}
+	return nil
+}
+
+// applyDiagnosis stores a diagnosis result and rebuilds the view.
+func (m *Model) applyDiagnosis(result msg.NamespaceDiagnosisMsg) {
+	d := m.Diagnosis
+	if d == nil || d.Namespace != result.Namespace {
+		return
+	}
+	first := d.Result == nil
+	d.Result = &result
+	d.Cursor = utils.Max(0, utils.Min(d.Cursor, len(result.Resources)-1))
+	if first {
+		// Start on the first resource that is holding things up
+		for i, r := range result.Resources {
+			if len(r.Finalizers) > 0 {
+				d.Cursor = i
+				break
+			}
+		}
+	}
+}
+
+// diagnosisLines renders the diagnosis and returns the line of the
+// selected resource, or -1.
+func (m *Model) diagnosisLines() ([]string, int) {
+	d := m.Diagnosis
+	r := d.Result
+	if r == nil {
+		return []string{"Diagnosing..."}, -1
+	}
+	if r.Err != nil {
+		return m.errorLines(r.Err), -1
+	}
+
+	var lines []string
+	phase := r.Phase
+	if !r.DeletionTime.IsZero() {
+		phase += fmt.Sprintf(" for %s (deletion requested %s)",
+			utils.HumanDuration(time.Since(r.DeletionTime)), r.DeletionTime.Local().Format("2006-01-02 15:04:05"))
+	}
+	lines = append(lines, "Phase: "+phase)
+	lines = append(lines, "Namespace finalizers: "+utils.OrDash(strings.Join(r.Finalizers, ", ")))
+	lines = append(lines, "")
+
+	lines = append(lines, InfoStyle.Render("Conditions"))
+	if len(r.Conditions) == 0 {
+		lines = append(lines, "  none reported")
+	} else {
+		rows := [][]string{}
+		for _, c := range r.Conditions {
+			rows = append(rows, []string{"  " + c.Type, c.Status, utils.OrDash(c.Reason), c.Message})
+		}
+		lines = append(lines, strings.Split(strings.TrimSuffix(formatTable(rows), "\n"), "\n")...)
+	}
+	lines = append(lines, "")
+
+	lines = append(lines, InfoStyle.Render(fmt.Sprintf("Remaining resources (%d)", len(r.Resources))))
+	if r.ListErr != nil {
+		lines = append(lines, ErrorStyle.Render(fmt.Sprintf("  Incomplete: %v", r.ListErr)))
+	}
+	if len(r.Resources) == 0 {
+		lines = append(lines, "  none found")
+		return lines, -1
+	}
+
+	rows := make([][]string, len(r.Resources))
+	for i, res := range r.Resources {
+		state := ""
+		if res.Deleting {
+			state = "deleting"
+		}
+		rows[i] = []string{res.Ref, state, utils.OrDash(strings.Join(res.Finalizers, ", "))}
+	}
+	table := strings.Split(strings.TrimSuffix(formatTable(rows), "\n"), "\n")
+
+	selected := -1
+	group := ""
+	for i, res := range r.Resources {
+		if res.Group != group {
+			group = res.Group
+			lines = append(lines, "  "+TitleStyle.Render(group))
+		}
+		line := "    " + table[i]
+		if i == d.Cursor {
+			selected = len(lines)
+			line = SelectedStyle.Render(line)
+		} else if len(res.Finalizers) > 0 {
+			line = TerminatingStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	return lines, selected
+}
+
+// renderDiagnosis shows the diagnosis view full screen.
+func (m *Model) renderDiagnosis() string {
+	d := m.Diagnosis
+	var b strings.Builder
+	b.WriteString(TitleStyle.Render(fmt.Sprintf("Diagnose namespace '%s'", d.Namespace)) + m.contextBadge() + m.connectionBadge() + m.readOnlyBadge())
+	b.WriteString("\n")
+
+	lines, selected := m.diagnosisLines()
+	p := d.Panel
+	p.Content = lines
+	p.MaxLines = utils.Max(1, m.Height-11)
+	// Keep the selected resource on screen
+	if selected >= 0 {
+		if selected < p.ScrollPos {
+			p.ScrollPos = selected
+		} else if selected >= p.ScrollPos+p.MaxLines {
+			p.ScrollPos = selected - p.MaxLines + 1
+		}
+	}
+	b.WriteString(m.renderPanel(p, true, m.Height-6, m.Width-2))
+	b.WriteString("\n")
+
+	if m.TypedConfirm != nil {
+		b.WriteString(m.renderTypedConfirm())
+	} else {
+		if d.Status != "" {
+			style := InfoStyle
+			if d.StatusErr {
+				style = ErrorStyle
+			}
+			b.WriteString(style.Render(d.Status) + "\n")
+		}
+		b.WriteString(Footer(
+			m.contextHint(),
+			m.Keys.PairHint(ActionUp, ActionDown, "Select"),
+			m.Keys.Hint(ActionClearFinal, "Clear finalizers"),
+			m.Keys.Hint(ActionRefresh, "Refresh"),
+			m.Keys.Hint(ActionCancel, "Close"),
+			m.Keys.Hint(ActionHelp, "Help"),
+			m.Keys.Hint(ActionQuit, "Quit"),
+		))
+	}
+	return lipgloss.NewStyle().MaxHeight(m.Height).Render(b.String())
+}
@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"strings"
+
+	"kubetbe/kubectl"
+)
+
+// errorLines explains err: what went wrong and how to fix it. For a
+// failed kubectl call it adds, while error details are on, the command and
+// everything kubectl printed.
+func (m *Model) errorLines(err error) []string {
+	e := kubectl.Explain(err)
+	lines := []string{ErrorStyle.Render("✗ " + e.Summary)}
+	if e.Fix != "" {
+		lines = append(lines, InfoStyle.Render("  Fix: "+e.Fix))
+	}
+	if e.Command == "" {
+		return lines
+	}
+	if m.ErrorDetails {
+		lines = append(lines, "  $ "+e.Command)
+		for _, line := range strings.Split(e.Raw, "\n") {
+			lines = append(lines, "  "+line)
+		}
+	}
+	if hint := m.errorDetailsHint(); hint != "" {
+		lines = append(lines, "  "+hint)
+	}
+	return lines
+}
+
+// renderError is errorLines as one block.
+func (m *Model) renderError(err error) string {
+	return strings.Join(m.errorLines(err), "\n")
+}
+
+// panelError is the error to show under the panels: a failed action, a
+// failed pods refresh, else a failure of the active log panel.
+func (m *Model) panelError() error {
+	if m.Err != nil {
+		return m.Err
+	}
+	if m.PodsPanel != nil && m.PodsPanel.Err != nil {
+		return m.PodsPanel.Err
+	}
+	if i := m.activeLogPanelIndex(); i >= 0 && i < len(m.LogsPanels) {
+		return m.LogsPanels[i].Err
+	}
+	return nil
+}
+
+// errorSummary is a one-line explanation of err, for lists of errors.
+func errorSummary(err error) string {
+	return kubectl.Explain(err).Summary
+}
+
+// errorDetailsHint names the key that shows or hides kubectl's output.
+func (m *Model) errorDetailsHint() string {
+	if !m.Keys.Bound(m.keyView(), ActionErrorInfo) {
+		return ""
+	}
+	if m.ErrorDetails {
+		return m.Keys.Hint(ActionErrorInfo, "Hide kubectl output")
+	}
+	return m.Keys.Hint(ActionErrorInfo, "Show kubectl output")
+}
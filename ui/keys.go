@@ -0,0 +1,280 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Action is something a key press can do. Update dispatches on actions, and
+// the footers and help overlay are generated from the same table, so the
+// advertised keys always match the handled ones.
+type Action string
+
+const (
+	ActionQuit       Action = "quit"
+	ActionHelp       Action = "help"
+	ActionUp         Action = "up"
+	ActionDown       Action = "down"
+	ActionPageUp     Action = "page_up"
+	ActionPageDown   Action = "page_down"
+	ActionTop        Action = "top"
+	ActionBottom     Action = "bottom"
+	ActionOpen       Action = "open"
+	ActionBack       Action = "back"
+	ActionCancel     Action = "cancel"
+	ActionRefresh    Action = "refresh"
+	ActionFilter     Action = "filter"
+	ActionFind       Action = "find"
+	ActionStar       Action = "star"
+	ActionFavorites  Action = "favorites"
+	ActionDelete     Action = "delete"
+	ActionDescribe   Action = "describe"
+	ActionNextPanel  Action = "next_panel"
+	ActionPrevPanel  Action = "prev_panel"
+	ActionNextNSPage Action = "next_page"
+	ActionPrevNSPage Action = "prev_page"
+	ActionAudit      Action = "audit"
+	ActionMark       Action = "mark"
+	ActionMarkMatch  Action = "mark_pattern"
+	ActionMarkStatus Action = "mark_status"
+	ActionDeleteWith Action = "delete_options"
+	ActionDiagnose   Action = "diagnose"
+	ActionClearFinal Action = "clear_finalizers"
+	ActionContext    Action = "context"
+	ActionErrorInfo  Action = "error_details"
+)
+
+// mutatingActions change cluster state. In read-only mode they are hidden
+// from footers and help and refused when pressed; the kubectl layer refuses
+// them as well.
+var mutatingActions = map[Action]bool{
+	ActionDelete:     true,
+	ActionDeleteWith: true,
+	ActionClearFinal: true,
+}
+
+// Views a binding can apply to.
+const (
+	viewNamespaces = "namespace_select"
+	viewPanels     = "panel_view"
+	viewAudit      = "audit"
+	viewDiagnose   = "diagnose"
+	viewContexts   = "contexts"
+)
+
+// Binding ties an action to its keys in one or more views.
+type Binding struct {
+	Action Action
+	Keys   []string
+	Help   string
+	Views  []string
+}
+
+// defaultBindings is the built-in keymap, in the order the help overlay
+// lists it.
+func defaultBindings() []Binding {
+	all := []string{viewNamespaces, viewPanels, viewAudit, viewDiagnose, viewContexts}
+	both := []string{viewNamespaces, viewPanels}
+	ns := []string{viewNamespaces}
+	panels := []string{viewPanels}
+	return []Binding{
+		{ActionUp, []string{"up", "k"}, "Move up / scroll up", all},
+		{ActionDown, []string{"down", "j"}, "Move down / scroll down", all},
+		{ActionPageUp, []string{"pgup"}, "Previous page / scroll a page up", all},
+		{ActionPageDown, []string{"pgdown"}, "Next page / scroll a page down", all},
+		{ActionTop, []string{"home"}, "Jump to top", all},
+		{ActionBottom, []string{"end"}, "Jump to bottom", all},
+		{ActionNextNSPage, []string{"right", "l", "tab"}, "Next page", ns},
+		{ActionPrevNSPage, []string{"left", "h", "shift+tab"}, "Previous page", ns},
+		{ActionOpen, []string{"enter"}, "Open namespace / lookup result; switch context", []string{viewNamespaces, viewContexts}},
+		{ActionFilter, []string{"/"}, "Filter namespaces", ns},
+		{ActionFind, []string{"f"}, "Find by IP, DNS name or port", ns},
+		{ActionCancel, []string{"esc"}, "Close results, audit log, diagnosis or context list; clear marks", all},
+		{ActionStar, []string{"s"}, "Star / unstar namespace", ns},
+		{ActionFavorites, []string{"F"}, "Show favorites only / all", ns},
+		{ActionRefresh, []string{"r"}, "Refresh", []string{viewNamespaces, viewAudit, viewDiagnose, viewContexts}},
+		{ActionDiagnose, []string{"T"}, "Diagnose a namespace stuck Terminating", []string{viewNamespaces, viewDiagnose}},
+		{ActionClearFinal, []string{"C"}, "Clear finalizers of the selected resource", []string{viewDiagnose}},
+		{ActionNextPanel, []string{"tab"}, "Next panel", panels},
+		{ActionPrevPanel, []string{"shift+tab"}, "Previous panel", panels},
+		{ActionDescribe, []string{"i"}, "Toggle describe", panels},
+		{ActionMark, []string{" "}, "Mark / unmark pod", panels},
+		{ActionMarkMatch, []string{"*"}, "Mark pods by name pattern", panels},
+		{ActionMarkStatus, []string{"S"}, "Mark every pod with the same status", panels},
+		{ActionBack, []string{"b"}, "Back to namespaces", []string{viewPanels, viewDiagnose}},
+		{ActionDelete, []string{"d"}, "Delete (press twice); deletes marked pods if any", both},
+		{ActionDeleteWith, []string{"D"}, "Delete / evict with options (grace period, force)", panels},
+		{ActionContext, []string{"c"}, "Switch kube context", []string{viewNamespaces, viewPanels, viewContexts}},
+		{ActionErrorInfo, []string{"e"}, "Show / hide kubectl's output for errors", []string{viewNamespaces, viewPanels, viewDiagnose, viewContexts}},
+		{ActionAudit, []string{"A"}, "Show / hide the audit log", all},
+		{ActionHelp, []string{"?"}, "Toggle this help", all},
+		{ActionQuit, []string{"q", "ctrl+c"}, "Quit", all},
+	}
+}
+
+// Keymap resolves key presses to actions per view.
+type Keymap struct {
+	Bindings []Binding
+	ReadOnly bool                         // hide mutating actions
+	byKey    map[string]map[string]Action // view -> key -> action
+}
+
+// NewKeymap builds the keymap from the defaults plus user overrides, which
+// map an action name to the keys that replace its default keys in every
+// view the action is used in. Unknown actions, empty key lists and keys
+// bound twice in one view are rejected.
+func NewKeymap(overrides map[string][]string) (*Keymap, error) {
+	bindings := defaultBindings()
+	known := map[Action]bool{}
+	for _, b := range bindings {
+		known[b.Action] = true
+	}
+
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		keys := overrides[name]
+		if !known[Action(name)] {
+			return nil, fmt.Errorf("keys: unknown action %q", name)
+		}
+		if len(keys) == 0 {
+			return nil, fmt.Errorf("keys: %s: at least one key is required", name)
+		}
+		for i := range bindings {
+			if bindings[i].Action == Action(name) {
+				bindings[i].Keys = keys
+			}
+		}
+	}
+
+	km := &Keymap{Bindings: bindings, byKey: map[string]map[string]Action{}}
+	for _, b := range bindings {
+		for _, view := range b.Views {
+			if km.byKey[view] == nil {
+				km.byKey[view] = map[string]Action{}
+			}
+			for _, key := range b.Keys {
+				if other, ok := km.byKey[view][key]; ok && other != b.Action {
+					return nil, fmt.Errorf("keys: %q is bound to both %s and %s", key, other, b.Action)
+				}
+				km.byKey[view][key] = b.Action
+			}
+		}
+	}
+	return km, nil
+}
+
+// Action returns the action bound to key in view, or "" if none.
+func (k *Keymap) Action(view, key string) Action {
+	return k.byKey[view][key]
+}
+
+// Disabled reports whether action is hidden and refused in read-only mode.
+func (k *Keymap) Disabled(action Action) bool {
+	return k.ReadOnly && mutatingActions[action]
+}
+
+// Bound reports whether action has a key in view.
+func (k *Keymap) Bound(view string, action Action) bool {
+	for _, key := range k.Keys(action) {
+		if k.Action(view, key) == action {
+			return true
+		}
+	}
+	return false
+}
+
+// Keys returns the keys bound to action.
+func (k *Keymap) Keys(action Action) []string {
+	for _, b := range k.Bindings {
+		if b.Action == action {
+			return b.Keys
+		}
+	}
+	return nil
+}
+
+// Hint renders "key: label" for a footer using the first key of action.
+func (k *Keymap) Hint(action Action, label string) string {
+	keys := k.Keys(action)
+	if len(keys) == 0 || k.Disabled(action) {
+		return ""
+	}
+	return displayKey(keys[0]) + ": " + label
+}
+
+// PairHint renders "a/b: label" for two related actions such as up/down.
+func (k *Keymap) PairHint(a, b Action, label string) string {
+	ka, kb := k.Keys(a), k.Keys(b)
+	if len(ka) == 0 || len(kb) == 0 {
+		return ""
+	}
+	return displayKey(ka[0]) + "/" + displayKey(kb[0]) + ": " + label
+}
+
+// Footer joins hints, skipping empty ones.
+func Footer(hints ...string) string {
+	var parts []string
+	for _, h := range hints {
+		if h != "" {
+			parts = append(parts, h)
+		}
+	}
+	return strings.Join(parts, " | ")
+}
+
+var keyNames = map[string]string{
+	"up":        "↑",
+	"down":      "↓",
+	"left":      "←",
+	"right":     "→",
+	"enter":     "Enter",
+	"esc":       "Esc",
+	"tab":       "Tab",
+	"shift+tab": "Shift+Tab",
+	"pgup":      "PgUp",
+	"pgdown":    "PgDn",
+	"home":      "Home",
+	"end":       "End",
+	"ctrl+c":    "Ctrl+C",
+	" ":         "Space",
+}
+
+// displayKey turns a Bubble Tea key string into what the user sees on the
+// keyboard.
+func displayKey(key string) string {
+	if name, ok := keyNames[key]; ok {
+		return name
+	}
+	return key
+}
+
+// helpLines renders the help overlay for view. Actions denied explains are
+// greyed out with the reason.
+func (k *Keymap) helpLines(view string, denied func(Action) string) []string {
+	rows := [][]string{}
+	for _, b := range k.Bindings {
+		if k.Disabled(b.Action) {
+			continue
+		}
+		for _, v := range b.Views {
+			if v != view {
+				continue
+			}
+			keys := make([]string, len(b.Keys))
+			for i, key := range b.Keys {
+				keys[i] = displayKey(key)
+			}
+			row := []string{strings.Join(keys, " / "), b.Help}
+			if why := denied(b.Action); why != "" {
+				row = []string{DisabledStyle.Render(row[0]), DisabledStyle.Render(row[1] + " (not allowed: " + why + ")")}
+			}
+			rows = append(rows, row)
+		}
+	}
+	return strings.Split(strings.TrimSuffix(formatTable(rows), "\n"), "\n")
+}
@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewKeymap(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides map[string][]string
+		wantErr   string // fragment of the error, "" for valid
+		view      string
+		key       string
+		want      Action // what key does in view
+	}{
+		{name: "defaults", view: viewPanels, key: "d", want: ActionDelete},
+		{name: "defaults bind tab per view", view: viewNamespaces, key: "tab", want: ActionNextNSPage},
+		{name: "same key in other views", view: viewPanels, key: "tab", want: ActionNextPanel},
+		{
+			name:      "override replaces the default keys",
+			overrides: map[string][]string{"delete": {"x"}},
+			view:      viewPanels, key: "x", want: ActionDelete,
+		},
+		{
+			name:      "overridden default key is free",
+			overrides: map[string][]string{"delete": {"x"}},
+			view:      viewPanels, key: "d", want: "",
+		},
+		{
+			name:      "override applies in every view",
+			overrides: map[string][]string{"delete": {"x", "delete"}},
+			view:      viewNamespaces, key: "delete", want: ActionDelete,
+		},
+		{
+			name:      "swapping two keys",
+			overrides: map[string][]string{"describe": {"d"}, "delete": {"i"}},
+			view:      viewPanels, key: "d", want: ActionDescribe,
+		},
+		{name: "unknown action", overrides: map[string][]string{"explode": {"x"}}, wantErr: `unknown action "explode"`},
+		{name: "no keys", overrides: map[string][]string{"delete": {}}, wantErr: "delete: at least one key is required"},
+		{name: "duplicate key", overrides: map[string][]string{"delete": {"q"}}, wantErr: `"q" is bound to both`},
+		{name: "duplicate key in one view only", overrides: map[string][]string{"describe": {"b"}}, wantErr: `"b" is bound to both`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			km, err := NewKeymap(tt.overrides)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("NewKeymap() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewKeymap() error = %v", err)
+			}
+			if got := km.Action(tt.view, tt.key); got != tt.want {
+				t.Errorf("Action(%q, %q) = %q, want %q", tt.view, tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeymapHints(t *testing.T) {
+	km, err := NewKeymap(map[string][]string{"delete": {"x"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := km.Hint(ActionDelete, "Delete"), "x: Delete"; got != want {
+		t.Errorf("Hint = %q, want %q", got, want)
+	}
+	if got, want := km.PairHint(ActionUp, ActionDown, "Select"), "↑/↓: Select"; got != want {
+		t.Errorf("PairHint = %q, want %q", got, want)
+	}
+
+	km.ReadOnly = true
+	if got := km.Hint(ActionDelete, "Delete"); got != "" {
+		t.Errorf("read-only Hint = %q, want it hidden", got)
+	}
+	if !km.Disabled(ActionDelete) || km.Disabled(ActionDescribe) {
+		t.Error("read-only mode should disable delete and nothing that only reads")
+	}
+}
@@ -0,0 +1,75 @@
+package ui
+
+import (
+	"context"
+	"strings"
+)
+
+// The kubectl calls kubetbe starts are owned by what is on screen. Reads for
+// the current view run under its context, which is cancelled when the view
+// is left; the pods panel and each log panel get a child of it, so dropping
+// one panel stops only its calls. Mutations run under the session context
+// instead, so a confirmed delete finishes even after navigating away.
+// Quitting cancels everything.
+
+// sessionContext is the context of the whole session.
+func (m *Model) sessionContext() context.Context {
+	if m.session == nil {
+		m.session, m.endSession = context.WithCancel(context.Background())
+	}
+	return m.session
+}
+
+// viewContext is the context of the current view, created on first use.
+func (m *Model) viewContext() context.Context {
+	if m.view == nil {
+		m.view, m.leave = context.WithCancel(m.sessionContext())
+	}
+	return m.view
+}
+
+// leaveView cancels every kubectl call the current view started. The next
+// call gets a fresh view context.
+func (m *Model) leaveView() {
+	if m.leave != nil {
+		m.leave()
+	}
+	m.view, m.leave = nil, nil
+	// Whatever was being listed has just been cancelled
+	m.NamespacesFetching = false
+}
+
+// Shutdown cancels every kubectl call still running, mutations included.
+// It is called on quit, and is safe to call more than once.
+func (m *Model) Shutdown() {
+	m.leaveView()
+	if m.endSession != nil {
+		m.endSession()
+	}
+}
+
+// panelContext is the context for p's calls, created on first use.
+func (m *Model) panelContext(p *Panel) context.Context {
+	if p.ctx == nil {
+		p.ctx, p.cancel = context.WithCancel(m.viewContext())
+	}
+	return p.ctx
+}
+
+// logContext is the context for the log calls of pod's panel.
+func (m *Model) logContext(pod string) context.Context {
+	for _, p := range m.LogsPanels {
+		if strings.TrimPrefix(p.Title, "Logs: ") == pod {
+			return m.panelContext(p)
+		}
+	}
+	return m.viewContext()
+}
+
+// stop cancels the calls started for the panel.
+func (p *Panel) stop() {
+	if p != nil && p.cancel != nil {
+		p.cancel()
+		p.ctx, p.cancel = nil, nil
+	}
+}
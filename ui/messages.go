@@ -8,8 +8,17 @@ type PodUpdateMsg = msg.PodUpdateMsg
 type LogUpdateMsg = msg.LogUpdateMsg
 type NamespaceListMsg = msg.NamespaceListMsg
 type NamespaceDeleteMsg = msg.NamespaceDeleteMsg
+type NamespaceDeletePreviewMsg = msg.NamespaceDeletePreviewMsg
 type PodDeleteMsg = msg.PodDeleteMsg
+type AccessMsg = msg.AccessMsg
+type BulkDeleteMsg = msg.BulkDeleteMsg
+type BulkDeleteDoneMsg = msg.BulkDeleteDoneMsg
+type DisruptionCheckMsg = msg.DisruptionCheckMsg
+type NamespaceDiagnosisMsg = msg.NamespaceDiagnosisMsg
+type FinalizersClearedMsg = msg.FinalizersClearedMsg
 type PodDescribeMsg = msg.PodDescribeMsg
 type ServiceLookupMsg = msg.ServiceLookupMsg
 type ErrorMsg = msg.ErrorMsg
 type StartLogLoadMsg = msg.StartLogLoadMsg
+type AuditLogMsg = msg.AuditLogMsg
+type ContextListMsg = msg.ContextListMsg
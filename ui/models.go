@@ -1,10 +1,21 @@
 package ui
 
-import "os/exec"
+import (
+	"context"
+	"time"
+
+	"kubetbe/config"
+	"kubetbe/kubectl"
+	"kubetbe/msg"
+)
 
 type Model struct {
-	State                 string // "namespace_select", "panel_view"
-	Namespaces            []string
+	State                 string              // "namespace_select", "panel_view"
+	AllNamespaces         []msg.NamespaceInfo // Every namespace from the last fetch
+	Namespaces            []msg.NamespaceInfo // AllNamespaces filtered and ranked by SearchTerm
+	NamespacesErr         error               // The last fetch failed; AllNamespaces is from before it
+	NamespacesUpdated     time.Time           // When AllNamespaces was last fetched successfully
+	NamespacesFetching    bool                // A listing is still running; ticks skip starting another
 	Cursor                int
 	SelectedNS            string
 	PodCursor             int
@@ -16,51 +27,119 @@ type Model struct {
 	Height                int
 	Err                   error
 	Quit                  bool
-	SearchTerm            string // Search term for namespace filtering
-	NamespaceWatch        bool   // Auto-refresh namespace list
-	DeleteConfirmation    string // Namespace to delete (empty if no confirmation pending)
-	DeletingNamespace     string // Namespace currently being deleted
-	PodDeleteConfirmation string // Pod to delete (empty if no confirmation pending)
-	DeletingPod           string // Pod currently being deleted
-	DescribePanel         *Panel // Panel to show describe output
-	DescribeTarget        string // Pod currently described
+	SearchTerm            string          // Search term for namespace filtering
+	NSFilterActive        bool            // Typing into the namespace filter prompt
+	NSFilterPrev          string          // SearchTerm before the prompt opened, restored on Esc
+	Config                *config.Config  // User configuration
+	Keys                  *Keymap         // Key bindings driving Update, footers and help
+	ShowHelp              bool            // Help overlay visible
+	ErrorDetails          bool            // Errors show the kubectl command and its raw output
+	UserState             *config.State   // Favorites and other state persisted across sessions
+	FavoritesOnly         bool            // Show only favorite namespaces
+	NamespaceWatch        bool            // Auto-refresh namespace list
+	DeleteConfirmation    string          // Namespace to delete (empty if no confirmation pending)
+	DeleteConfirmContext  string          // Context of DeleteConfirmation in multi-cluster mode
+	DeletingNamespace     string          // Namespace currently being deleted
+	PodDeleteConfirmation string          // Pod to delete (empty if no confirmation pending)
+	DeletingPod           string          // Pod currently being deleted
+	MarkedPods            map[string]bool // Pods marked for a bulk action
+	PodPatternActive      bool            // Typing into the mark-by-pattern prompt
+	PodPattern            string
+	BulkDeleteConfirm     bool                           // Waiting for the second delete key to delete every marked pod
+	BulkDelete            *BulkDelete                    // Running or finished bulk delete
+	DeleteDialog          *DeleteDialog                  // Delete options (grace period, force, evict) being chosen
+	TypedConfirm          *TypedConfirm                  // Typed-name confirmation for protected namespaces
+	DeletePreview         *msg.NamespaceDeletePreviewMsg // Server dry-run of the namespace delete being confirmed
+	DeletePreviewLoading  bool                           // DeletePreview is still running
+	DescribePanel         *Panel                         // Panel to show describe output
+	AuditPanel            *Panel                         // Recent audit log entries, shown over the current view
+	Diagnosis             *Diagnosis                     // Terminating namespace diagnosis, shown over the namespace list
+	ContextPicker         *ContextPicker                 // Kube context list, shown over the current view
+	KubeContext           string                         // Context passed to kubectl with --context, "" for kubectl's current one
+	NamespaceContext      string                         // Context the open namespace was listed from in multi-cluster mode, "" otherwise
+	DefaultContext        string                         // kubectl's current context, from the kubeconfig
+	MultiContexts         []string                       // Contexts merged into the namespace list; empty outside multi-cluster mode
+	Clusters              []msg.ClusterStatus            // How each of MultiContexts fared in the last listing
+	ClustersUpdated       map[string]time.Time           // When each of MultiContexts was last listed successfully
+	DescribeTarget        string                         // Pod currently described
 	ServiceIPQuery        string
-	ServiceIPResult       []string
+	ServiceIPResult       []msg.LookupMatch
 	ServiceIPSearching    bool
 	ServiceIPInputActive  bool
 	ServiceIPErr          error
+	ServiceIPListErr      error  // kinds the last lookup could not list
+	ServiceIPCursor       int    // Selected row in ServiceIPResult
+	PodSelector           string // Label selector applied to the pods panel (empty for all pods)
 	NSTotalPages          int
 	NSCurrentPage         int
 	AvailablePods         []string // List of all pods (for lazy log loading)
-	PendingLogLoad        string  // Pod name waiting for log load (empty if none)
+	PendingLogLoad        string   // Pod name waiting for log load (empty if none)
+
+	// Contexts owning the kubectl calls in flight; see lifecycle.go
+	session    context.Context
+	endSession context.CancelFunc
+	view       context.Context
+	leave      context.CancelFunc
 }
 
+// logsLoading is the content of a log panel until its first fetch returns.
+const logsLoading = "Loading logs..."
+
 type Panel struct {
 	Title     string
 	Content   []string
 	MaxLines  int
 	ScrollPos int
-	UpdateCmd *exec.Cmd
 	Watch     bool
+	Err       error     // the last fetch failed; Content is from before it
+	Updated   time.Time // when Content was last fetched successfully
+	Fetching  bool      // a tick's fetch is still running, e.g. retrying
+
+	// Owns the panel's kubectl calls; see panelContext
+	ctx    context.Context
+	cancel context.CancelFunc
 }
 
-func InitialModel(searchTerm string) *Model {
+// Options configure a new Model.
+type Options struct {
+	SearchTerm string
+	Config     *config.Config
+	Keys       *Keymap
+	UserState  *config.State
+	// Context ends every kubectl call when done, e.g. on a signal. Nil
+	// means the calls only end on quit.
+	Context context.Context
+}
+
+func InitialModel(opts Options) *Model {
+	parent := opts.Context
+	if parent == nil {
+		parent = context.Background()
+	}
+	session, endSession := context.WithCancel(parent)
 	return &Model{
 		State:                 "namespace_select",
-		Namespaces:            []string{},
+		AllNamespaces:         []msg.NamespaceInfo{},
+		Namespaces:            []msg.NamespaceInfo{},
 		Cursor:                0,
 		PodCursor:             0,
 		PodsPanel:             nil,
 		LogsPanels:            []*Panel{},
 		ActivePanel:           0,
-		SearchTerm:            searchTerm,
+		SearchTerm:            opts.SearchTerm,
+		Config:                opts.Config,
+		Keys:                  opts.Keys,
+		UserState:             opts.UserState,
 		DeletingNamespace:     "",
 		PodDeleteConfirmation: "",
 		DeletingPod:           "",
+		MarkedPods:            map[string]bool{},
+		KubeContext:           kubectl.Context(),
+		MultiContexts:         opts.Config.Contexts,
 		DescribePanel:         nil,
 		DescribeTarget:        "",
 		ServiceIPQuery:        "",
-		ServiceIPResult:       []string{},
+		ServiceIPResult:       []msg.LookupMatch{},
 		ServiceIPSearching:    false,
 		ServiceIPInputActive:  false,
 		ServiceIPErr:          nil,
@@ -68,5 +147,7 @@ func InitialModel(searchTerm string) *Model {
 		NSCurrentPage:         0,
 		AvailablePods:         []string{},
 		PendingLogLoad:        "",
+		session:               session,
+		endSession:            endSession,
 	}
 }
@@ -0,0 +1,90 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"kubetbe/msg"
+	"kubetbe/utils"
+)
+
+// namespaceColumns returns the table cells for one namespace row.
+// labelKeys are the labels of interest shown in the LABELS column.
+func namespaceColumns(ns msg.NamespaceInfo, favorite bool, labelKeys []string, now time.Time) []string {
+	age := "-"
+	if !ns.Created.IsZero() {
+		age = utils.HumanDuration(now.Sub(ns.Created))
+	}
+	running, pending, failing := "?", "?", "?"
+	if ns.PodsKnown {
+		running = fmt.Sprint(ns.Running)
+		pending = fmt.Sprint(ns.Pending)
+		failing = fmt.Sprint(ns.Failing)
+	}
+	star := " "
+	if favorite {
+		star = "★"
+	}
+	return []string{star + namespaceHealth(ns), ns.Name, utils.OrDash(ns.Phase), age, running, pending, failing, namespaceLabels(ns, labelKeys)}
+}
+
+var namespaceHeader = []string{" ", "NAME", "STATUS", "AGE", "RUNNING", "PENDING", "FAILING", "LABELS"}
+
+// namespaceHealth is a one-character summary of a namespace's pods.
+func namespaceHealth(ns msg.NamespaceInfo) string {
+	switch {
+	case ns.Phase == "Terminating":
+		return "~"
+	case !ns.PodsKnown:
+		return "?"
+	case ns.Failing > 0:
+		return "✗"
+	case ns.Pending > 0:
+		return "…"
+	}
+	return "✓"
+}
+
+func namespaceLabels(ns msg.NamespaceInfo, keys []string) string {
+	var parts []string
+	for _, key := range keys {
+		if v, ok := ns.Labels[key]; ok {
+			parts = append(parts, key+"="+v)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// namespaceRowStyle picks the style of an unselected namespace row so broken
+// or terminating namespaces stand out.
+func namespaceRowStyle(ns msg.NamespaceInfo) lipgloss.Style {
+	switch {
+	case ns.Phase == "Terminating":
+		return TerminatingStyle
+	case ns.PodsKnown && ns.Failing > 0:
+		return FailingStyle
+	}
+	return NormalStyle
+}
+
+// FilterNamespaces returns the namespaces fuzzy-matching term, favorites
+// first and then best match first. With an empty term each group is in
+// name order. favoritesOnly drops everything that is not a favorite.
+func FilterNamespaces(all []msg.NamespaceInfo, term string, isFavorite func(string) bool, favoritesOnly bool) []msg.NamespaceInfo {
+	names := make([]string, len(all))
+	for i, ns := range all {
+		names[i] = ns.Name
+	}
+	favorite := func(i int) bool { return isFavorite(names[i]) }
+
+	result := []msg.NamespaceInfo{}
+	for _, i := range utils.FuzzyRank(term, names, favorite) {
+		if !favoritesOnly || favorite(i) {
+			result = append(result, all[i])
+		}
+	}
+	return result
+}
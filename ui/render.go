@@ -3,9 +3,11 @@ package ui
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 
+	"kubetbe/msg"
 	"kubetbe/utils"
 )
 
@@ -18,6 +20,26 @@ func (m *Model) View() string {
 		return "Loading..."
 	}
 
+	if m.ShowHelp {
+		return m.renderHelp()
+	}
+
+	if m.AuditPanel != nil {
+		return m.renderAudit()
+	}
+
+	if m.ContextPicker != nil {
+		return m.renderContextPicker()
+	}
+
+	if m.DeleteDialog != nil {
+		return m.renderDeleteDialog()
+	}
+
+	if m.Diagnosis != nil {
+		return m.renderDiagnosis()
+	}
+
 	if m.State == "namespace_select" {
 		return m.renderNamespaceSelect()
 	}
@@ -28,17 +50,41 @@ func (m *Model) View() string {
 func (m *Model) renderNamespaceSelect() string {
 	var b strings.Builder
 
-	b.WriteString(TitleStyle.Render("Kubernetes Helper - Select Namespace"))
+	title := "Kubernetes Helper - Select Namespace"
+	if m.FavoritesOnly {
+		title += " (favorites)"
+	}
+	title += m.namespacesStale()
+	b.WriteString(TitleStyle.Render(title) + m.contextBadge() + m.connectionBadge() + m.readOnlyBadge())
 	b.WriteString("\n\n")
 
 	if m.Err != nil {
-		b.WriteString(ErrorStyle.Render(fmt.Sprintf("Error: %v\n\n", m.Err)))
+		b.WriteString(m.renderError(m.Err) + "\n\n")
+	}
+
+	if m.NamespacesErr != nil {
+		b.WriteString(m.renderError(m.NamespacesErr) + "\n\n")
+	}
+
+	if status := m.renderClusterStatus(); status != "" {
+		b.WriteString(status + "\n")
+	}
+
+	if m.NSFilterActive {
+		b.WriteString(SelectedStyle.Render("Filter: " + m.SearchTerm + "_"))
+		b.WriteString(fmt.Sprintf("  %d/%d  Enter: Apply, Esc: Cancel, Ctrl+U: Clear\n\n", len(m.Namespaces), len(m.AllNamespaces)))
+	} else if m.SearchTerm != "" {
+		b.WriteString(InfoStyle.Render(fmt.Sprintf("Filter: %s", m.SearchTerm)))
+		b.WriteString(fmt.Sprintf("  %d/%d  (/ to edit)\n\n", len(m.Namespaces), len(m.AllNamespaces)))
 	}
 
 	if len(m.Namespaces) == 0 {
-		if m.SearchTerm != "" {
+		if m.FavoritesOnly {
+			b.WriteString("No favorite namespaces match...\n")
+			b.WriteString("Press s on a namespace to star it, F to show all namespaces\n")
+		} else if m.SearchTerm != "" {
 			b.WriteString(fmt.Sprintf("No namespaces found matching '%s'...\n", m.SearchTerm))
-			b.WriteString("Try running without search term to see all namespaces\n")
+			b.WriteString("Press / to edit or clear the filter\n")
 		} else {
 			b.WriteString("No namespaces found...\n")
 			b.WriteString("Command: kubectl get namespaces\n")
@@ -56,19 +102,27 @@ func (m *Model) renderNamespaceSelect() string {
 		if end > len(m.Namespaces) {
 			end = len(m.Namespaces)
 		}
+		// Column widths come from the whole list so they don't jump between pages
+		now := time.Now()
+		rows := [][]string{m.withCluster(namespaceHeader, "CLUSTER")}
+		for _, ns := range m.Namespaces {
+			cols := namespaceColumns(ns, m.UserState.IsFavorite(ns.Name), m.Config.NamespaceLabels, now)
+			if ns.Name == m.DeletingNamespace {
+				cols[1] = fmt.Sprintf("%s (deleting...)", ns.Name)
+			}
+			rows = append(rows, m.withCluster(cols, ns.Context))
+		}
+		lines := strings.Split(strings.TrimSuffix(formatTable(rows), "\n"), "\n")
+
+		b.WriteString(fmt.Sprintf("  %s\n", NormalStyle.Render(lines[0])))
 		for i := start; i < end; i++ {
-			ns := m.Namespaces[i]
 			cursor := " "
-			style := NormalStyle
+			style := namespaceRowStyle(m.Namespaces[i])
 			if i == m.Cursor {
 				cursor = ">"
 				style = SelectedStyle
 			}
-			displayName := ns
-			if ns == m.DeletingNamespace {
-				displayName = fmt.Sprintf("%s (deleting...)", ns)
-			}
-			b.WriteString(fmt.Sprintf("%s %s\n", cursor, style.Render(displayName)))
+			b.WriteString(fmt.Sprintf("%s %s\n", cursor, style.Render(lines[i+1])))
 		}
 		if m.NSTotalPages > 1 {
 			b.WriteString(InfoStyle.Render(fmt.Sprintf("\nPage %d/%d\n", m.NSCurrentPage+1, m.NSTotalPages)))
@@ -79,25 +133,32 @@ func (m *Model) renderNamespaceSelect() string {
 
 	// Service lookup section
 	if m.ServiceIPInputActive || m.ServiceIPSearching || m.ServiceIPErr != nil || len(m.ServiceIPResult) > 0 {
-		b.WriteString(TitleStyle.Render("Find service by IP"))
+		b.WriteString(TitleStyle.Render("Find by IP, DNS name or port"))
 		b.WriteString("\n")
 		if m.ServiceIPInputActive {
-			inputLabel := "IP: "
+			inputLabel := "Find: "
 			inputValue := m.ServiceIPQuery
 			b.WriteString(SelectedStyle.Render(inputLabel + inputValue + "_"))
 			b.WriteString("\n")
-			b.WriteString("Enter IP and press Enter to search, Esc to cancel\n")
+			b.WriteString("Enter an IP, CIDR, service/ingress hostname or port and press Enter to search, Esc to cancel\n")
 		} else if m.ServiceIPQuery != "" {
-			b.WriteString(fmt.Sprintf("IP: %s\n", m.ServiceIPQuery))
+			b.WriteString(fmt.Sprintf("Find: %s\n", m.ServiceIPQuery))
 		}
 		if m.ServiceIPSearching {
-			b.WriteString(InfoStyle.Render("Searching services...\n"))
+			b.WriteString(InfoStyle.Render("Searching cluster resources...\n"))
 		} else if m.ServiceIPErr != nil {
-			b.WriteString(ErrorStyle.Render(fmt.Sprintf("Error: %v\n", m.ServiceIPErr)))
+			b.WriteString(m.renderError(m.ServiceIPErr) + "\n")
 		} else if len(m.ServiceIPResult) > 0 {
-			for _, line := range m.ServiceIPResult {
-				b.WriteString(line + "\n")
-			}
+			b.WriteString(renderLookupResults(m.ServiceIPResult, m.ServiceIPCursor))
+			b.WriteString(InfoStyle.Render(Footer(
+				m.Keys.PairHint(ActionUp, ActionDown, "Select result"),
+				m.Keys.Hint(ActionOpen, "Open namespace"),
+				m.Keys.Hint(ActionCancel, "Close results"),
+			)))
+			b.WriteString("\n")
+		}
+		if !m.ServiceIPSearching && m.ServiceIPListErr != nil {
+			b.WriteString(ErrorStyle.Render(fmt.Sprintf("Incomplete: %v", m.ServiceIPListErr)) + "\n")
 		}
 		b.WriteString("\n")
 	}
@@ -106,24 +167,85 @@ func (m *Model) renderNamespaceSelect() string {
 		b.WriteString(InfoStyle.Render(fmt.Sprintf("Deleting namespace '%s'...\n", m.DeletingNamespace)))
 	}
 
+	if m.TypedConfirm != nil {
+		b.WriteString("\n" + m.renderTypedConfirm() + "\n")
+		if preview := m.renderDeletePreview(m.TypedConfirm.Expected); preview != "" {
+			b.WriteString(preview + "\n")
+		}
+	}
+
 	// Show delete confirmation if pending
 	if m.DeleteConfirmation != "" {
-		b.WriteString(ErrorStyle.Render(fmt.Sprintf("\n⚠️  Delete namespace '%s'? Press 'd' again to confirm, any other key to cancel\n", m.DeleteConfirmation)))
+		b.WriteString("\n" + ErrorStyle.Render(fmt.Sprintf("⚠️  Delete namespace '%s'? Press '%s' again to confirm, any other key to cancel", m.DeleteConfirmation, m.keyFor(ActionDelete))) + "\n")
+		if preview := m.renderDeletePreview(m.DeleteConfirmation); preview != "" {
+			b.WriteString(preview + "\n")
+		}
+	}
+
+	// Point at the diagnosis when the selected namespace is stuck
+	diagnoseHint := ""
+	if m.Cursor < len(m.Namespaces) && m.Namespaces[m.Cursor].Phase == "Terminating" {
+		diagnoseHint = m.Keys.Hint(ActionDiagnose, "Why still Terminating?")
 	}
 
 	// Show help text
-	helpText := "↑↓: Select, Enter: Confirm, Tab/Shift+Tab or ←→: Page, f: Find service"
-	if m.NamespaceWatch {
-		helpText += ", R: Refresh, d: Delete"
-	} else {
-		helpText += ", R: Refresh, d: Delete"
+	b.WriteString(Footer(
+		m.contextHint(),
+		m.Keys.PairHint(ActionUp, ActionDown, "Select"),
+		diagnoseHint,
+		m.Keys.Hint(ActionOpen, "Open"),
+		m.Keys.PairHint(ActionPrevNSPage, ActionNextNSPage, "Page"),
+		m.Keys.Hint(ActionFilter, "Filter"),
+		m.Keys.Hint(ActionStar, "Star"),
+		m.Keys.Hint(ActionFavorites, "Favorites"),
+		m.Keys.Hint(ActionFind, "Find"),
+		m.Keys.Hint(ActionRefresh, "Refresh"),
+		m.actionHint(ActionDelete, "Delete"),
+		m.Keys.Hint(ActionAudit, "Audit log"),
+		m.Keys.Hint(ActionHelp, "Help"),
+		m.Keys.Hint(ActionQuit, "Quit"),
+	))
+	if note := m.accessNote(ActionDelete); note != "" {
+		b.WriteString("\n" + note)
 	}
-	helpText += ", q: Quit"
-	b.WriteString(helpText)
 
 	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, b.String())
 }
 
+// renderHelp shows every binding of the current view, generated from the
+// keymap so it reflects user overrides.
+func (m *Model) renderHelp() string {
+	var b strings.Builder
+	view := m.keyView()
+	title := "Keys - Namespaces"
+	switch view {
+	case viewPanels:
+		title = "Keys - Pods & Logs"
+	case viewAudit:
+		title = "Keys - Audit log"
+	case viewDiagnose:
+		title = "Keys - Namespace diagnosis"
+	case viewContexts:
+		title = "Keys - Kube contexts"
+	}
+	b.WriteString(TitleStyle.Render(title) + m.contextBadge() + m.connectionBadge())
+	b.WriteString("\n\n")
+	for _, line := range m.Keys.helpLines(view, m.denied) {
+		b.WriteString(line + "\n")
+	}
+	b.WriteString("\nPress any key to close")
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, PanelStyle.Render(b.String()))
+}
+
+// keyFor returns the first key bound to action, as shown to the user.
+func (m *Model) keyFor(action Action) string {
+	keys := m.Keys.Keys(action)
+	if len(keys) == 0 {
+		return ""
+	}
+	return displayKey(keys[0])
+}
+
 func (m *Model) renderPanelView() string {
 	if m.PodsPanel == nil {
 		return "Loading pods..."
@@ -135,8 +257,7 @@ func (m *Model) renderPanelView() string {
 
 	// Pods panel is fixed at the top with a reasonable height
 	// This ensures it stays visible and can show more pods with scroll
-	// Increased height to show more pods (12 lines can show ~8-10 pods)
-	podsPanelHeight := 12 // Fixed height for pods panel (includes border/padding)
+	podsPanelHeight := m.Config.PodsPanelHeight // Fixed height for pods panel (includes border/padding)
 
 	// Remaining height goes to single active log panel
 	// Show only ONE log panel at a time (Tab to switch between them)
@@ -153,9 +274,9 @@ func (m *Model) renderPanelView() string {
 
 	// Update panel maxLines (subtract border and padding: ~3 lines)
 	if m.PodsPanel != nil {
-		// Pods panel has fixed height (12), allow more content with scroll
+		// Pods panel has fixed height, allow more content with scroll
 		// This ensures it never exceeds its allocated space but can scroll to show more
-		m.PodsPanel.MaxLines = 7 // Maximum 7 lines of visible content (12 - 5 for border/padding/title)
+		m.PodsPanel.MaxLines = podsPanelHeight - 5 // Subtract border/padding/title
 	}
 	for _, p := range m.LogsPanels {
 		// Log panels: use available height since we show only one at a time
@@ -229,7 +350,12 @@ func (m *Model) renderPanelView() string {
 	// Render it with fixed height regardless of activePanel state
 	// Pass activePodName to highlight the active pod in the list
 	if m.PodsPanel != nil {
-		podsContent := m.renderPanelWithHighlight(m.PodsPanel, m.ActivePanel == 0, podsPanelHeight, m.Width, highlightPodName)
+		// Render a copy with mark and bulk delete markers so parsing the
+		// real content still sees plain kubectl output
+		pods := *m.PodsPanel
+		pods.Content = m.markedPodLines(m.PodsPanel.Content)
+		podsContent := m.renderPanelWithHighlight(&pods, m.ActivePanel == 0, podsPanelHeight, m.Width, highlightPodName)
+		m.PodsPanel.ScrollPos = pods.ScrollPos
 		// CRITICAL: Ensure pods panel doesn't exceed its allocated height
 		// This prevents overlapping with log panels
 		podsLines := strings.Split(podsContent, "\n")
@@ -305,15 +431,21 @@ func (m *Model) renderPanelView() string {
 		if len(describeDisplay) > 40 {
 			describeDisplay = describeDisplay[:37] + "..."
 		}
-		action := "i: Close describe"
+		switchHint := ""
 		if len(m.LogsPanels) > 0 {
-			action += " | Tab: Switch"
+			switchHint = m.Keys.Hint(ActionNextPanel, "Switch")
 		}
-		footer = fmt.Sprintf(
-			"\n%s | Describe: %s | %s | ↑↓: Scroll | d: Delete pod | b: Back | q: Quit",
-			TitleStyle.Render(fmt.Sprintf("Namespace: %s", m.SelectedNS)),
-			describeDisplay,
-			action,
+		footer = "\n" + Footer(
+			TitleStyle.Render(m.namespaceLabel())+m.connectionBadge()+m.readOnlyBadge(),
+			m.contextHint(),
+			"Describe: "+describeDisplay,
+			m.Keys.Hint(ActionDescribe, "Close describe"),
+			switchHint,
+			m.Keys.PairHint(ActionUp, ActionDown, "Scroll"),
+			m.actionHint(ActionDelete, "Delete pod"),
+			m.Keys.Hint(ActionBack, "Back"),
+			m.Keys.Hint(ActionHelp, "Help"),
+			m.Keys.Hint(ActionQuit, "Quit"),
 		)
 	} else if len(m.LogsPanels) > 0 {
 		activeLogIndex := -1
@@ -336,24 +468,55 @@ func (m *Model) renderPanelView() string {
 			}
 		}
 
-		footer = fmt.Sprintf(
-			"\n%s | Active: %s | Tab: Switch (%d/%d) | ↑↓: Scroll | i: Describe | d: Delete pod | b: Back | q: Quit",
-			TitleStyle.Render(fmt.Sprintf("Namespace: %s", m.SelectedNS)),
-			activePodDisplay,
-			currentPanel, totalPanels,
+		footer = "\n" + Footer(
+			TitleStyle.Render(m.namespaceLabel())+m.connectionBadge()+m.readOnlyBadge(),
+			m.contextHint(),
+			"Active: "+activePodDisplay,
+			m.Keys.Hint(ActionNextPanel, fmt.Sprintf("Switch (%d/%d)", currentPanel, totalPanels)),
+			m.Keys.PairHint(ActionUp, ActionDown, "Scroll"),
+			m.Keys.Hint(ActionDescribe, "Describe"),
+			m.Keys.Hint(ActionMark, "Mark"),
+			m.actionHint(ActionDelete, "Delete pod"),
+			m.actionHint(ActionDeleteWith, "Delete options"),
+			m.Keys.Hint(ActionBack, "Back"),
+			m.Keys.Hint(ActionHelp, "Help"),
+			m.Keys.Hint(ActionQuit, "Quit"),
 		)
 	} else {
-		footer = fmt.Sprintf(
-			"\n%s | Tab: Switch panel | ↑↓: Scroll | PgUp/PgDn: Page | Home/End: Jump | i: Describe | d: Delete pod | b: Back | q: Quit",
-			TitleStyle.Render(fmt.Sprintf("Namespace: %s", m.SelectedNS)),
+		footer = "\n" + Footer(
+			TitleStyle.Render(m.namespaceLabel())+m.connectionBadge()+m.readOnlyBadge(),
+			m.contextHint(),
+			m.Keys.Hint(ActionNextPanel, "Switch panel"),
+			m.Keys.PairHint(ActionUp, ActionDown, "Scroll"),
+			m.Keys.PairHint(ActionPageUp, ActionPageDown, "Page"),
+			m.Keys.PairHint(ActionTop, ActionBottom, "Jump"),
+			m.Keys.Hint(ActionDescribe, "Describe"),
+			m.Keys.Hint(ActionMark, "Mark"),
+			m.actionHint(ActionDelete, "Delete pod"),
+			m.actionHint(ActionDeleteWith, "Delete options"),
+			m.Keys.Hint(ActionBack, "Back"),
+			m.Keys.Hint(ActionHelp, "Help"),
+			m.Keys.Hint(ActionQuit, "Quit"),
 		)
 	}
 
+	if note := m.accessNote(ActionDelete, ActionDeleteWith); note != "" {
+		footer += "\n" + note
+	}
+	if err := m.panelError(); err != nil {
+		footer += "\n" + m.renderError(err)
+	}
 	if m.DeletingPod != "" {
 		footer += "\n" + InfoStyle.Render(fmt.Sprintf("Deleting pod '%s'...", m.DeletingPod))
 	}
+	if m.TypedConfirm != nil {
+		footer += "\n" + m.renderTypedConfirm()
+	}
 	if m.PodDeleteConfirmation != "" {
-		footer += "\n" + ErrorStyle.Render(fmt.Sprintf("⚠️  Delete pod '%s'? Press 'd' again to confirm, any other key to cancel", m.PodDeleteConfirmation))
+		footer += "\n" + ErrorStyle.Render(fmt.Sprintf("⚠️  Delete pod '%s'? Press '%s' again to confirm, any other key to cancel", m.PodDeleteConfirmation, m.keyFor(ActionDelete)))
+	}
+	if status := m.renderBulkStatus(); status != "" {
+		footer += "\n" + status
 	}
 
 	return combined + footer
@@ -480,7 +643,7 @@ func (m Model) renderPanelWithHighlight(p *Panel, active bool, maxHeight int, wi
 	content = strings.Join(visibleLines, "\n")
 
 	// Add scroll indicator to title
-	title := p.Title
+	title := p.Title + panelStale(p)
 	if len(lines) > displayLines {
 		currentPage := p.ScrollPos/displayLines + 1
 		totalPages := (len(lines) + displayLines - 1) / displayLines
@@ -490,7 +653,7 @@ func (m Model) renderPanelWithHighlight(p *Panel, active bool, maxHeight int, wi
 
 	style := PanelStyle
 	if active {
-		style = style.BorderForeground(lipgloss.Color("229"))
+		style = ActivePanelStyle
 	}
 
 	// Build panel content: title + content
@@ -580,7 +743,7 @@ func (m Model) renderPanel(p *Panel, active bool, maxHeight int, width int) stri
 	content = strings.Join(visibleLines, "\n")
 
 	// Add scroll indicator to title
-	title := p.Title
+	title := p.Title + panelStale(p)
 	if len(lines) > displayLines {
 		currentPage := p.ScrollPos/displayLines + 1
 		totalPages := (len(lines) + displayLines - 1) / displayLines
@@ -590,7 +753,7 @@ func (m Model) renderPanel(p *Panel, active bool, maxHeight int, width int) stri
 
 	style := PanelStyle
 	if active {
-		style = style.BorderForeground(lipgloss.Color("229"))
+		style = ActivePanelStyle
 	}
 
 	// Build panel content: title + content
@@ -627,3 +790,77 @@ func (m Model) renderPanel(p *Panel, active bool, maxHeight int, width int) stri
 	}
 	return style.Height(maxHeight).Render(panelContent)
 }
+
+// renderLookupResults lays out lookup matches as an aligned table with the
+// selected row highlighted.
+func renderLookupResults(matches []msg.LookupMatch, cursor int) string {
+	// Matches from a multi-cluster lookup name their cluster
+	multi := len(matches) > 0 && matches[0].Context != ""
+	header := []string{"KIND", "NAMESPACE", "NAME", "FIELD", "ADDRESS", "OWNER"}
+	if multi {
+		header = append([]string{"CLUSTER"}, header...)
+	}
+	rows := [][]string{header}
+	for _, r := range matches {
+		row := []string{r.Kind, utils.OrDash(r.Namespace), r.Name, r.Field, r.Address, utils.OrDash(r.Owner)}
+		if multi {
+			row = append([]string{r.Context}, row...)
+		}
+		rows = append(rows, row)
+	}
+	lines := strings.Split(strings.TrimSuffix(formatTable(rows), "\n"), "\n")
+
+	var b strings.Builder
+	b.WriteString("  " + lines[0] + "\n")
+	for i, line := range lines[1:] {
+		if i == cursor {
+			b.WriteString("> " + SelectedStyle.Render(line) + "\n")
+		} else {
+			b.WriteString("  " + line + "\n")
+		}
+	}
+	return b.String()
+}
+
+// formatTable pads each column to its widest cell, like kubectl's output.
+// Every row is padded to the same width so centered layouts stay aligned.
+func formatTable(rows [][]string) string {
+	widths := []int{}
+	for _, row := range rows {
+		for i, cell := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = utils.Max(widths[i], lipgloss.Width(cell))
+		}
+	}
+	var b strings.Builder
+	for _, row := range rows {
+		for i, cell := range row {
+			if i > 0 {
+				b.WriteString("   ")
+			}
+			b.WriteString(cell + strings.Repeat(" ", widths[i]-lipgloss.Width(cell)))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// readOnlyBadge marks every screen while mutating actions are disabled.
+func (m *Model) readOnlyBadge() string {
+	if !m.Config.ReadOnly {
+		return ""
+	}
+	return " " + BadgeStyle.Render("READ-ONLY")
+}
+
+// namespaceLabel names the open namespace and, when the pods panel is
+// filtered, the selector in use.
+func (m *Model) namespaceLabel() string {
+	label := fmt.Sprintf("Namespace: %s", m.SelectedNS)
+	if m.PodSelector != "" {
+		label += fmt.Sprintf(" (%s)", m.PodSelector)
+	}
+	return label
+}
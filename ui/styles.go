@@ -1,32 +1,211 @@
 package ui
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
 
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// The styles used across the UI. They are set by ApplyTheme and start out
+// with the dark theme.
 var (
+	TitleStyle       lipgloss.Style
+	SelectedStyle    lipgloss.Style
+	NormalStyle      lipgloss.Style
+	PanelStyle       lipgloss.Style
+	ActivePanelStyle lipgloss.Style
+	ErrorStyle       lipgloss.Style
+	InfoStyle        lipgloss.Style
+	FailingStyle     lipgloss.Style
+	TerminatingStyle lipgloss.Style
+	BadgeStyle       lipgloss.Style
+	ContextStyle     lipgloss.Style
+	DisabledStyle    lipgloss.Style
+)
+
+func init() {
+	applyPalette(Themes["dark"])
+}
+
+// Theme is a named color palette of 256-color codes.
+type Theme struct {
+	Title        string
+	SelectedFg   string
+	SelectedBg   string
+	Normal       string
+	Border       string
+	ActiveBorder string
+	Error        string
+	Info         string
+	Failing      string
+	Terminating  string
+}
+
+// Themes are the palettes selectable with the theme config option, plus
+// "monochrome", which uses no color at all.
+var Themes = map[string]Theme{
+	"dark": {
+		Title: "62", SelectedFg: "229", SelectedBg: "57", Normal: "245",
+		Border: "62", ActiveBorder: "229", Error: "196", Info: "110",
+		Failing: "203", Terminating: "214",
+	},
+	"light": {
+		Title: "25", SelectedFg: "16", SelectedBg: "153", Normal: "238",
+		Border: "25", ActiveBorder: "166", Error: "160", Info: "24",
+		Failing: "160", Terminating: "130",
+	},
+	"high-contrast": {
+		Title: "15", SelectedFg: "16", SelectedBg: "11", Normal: "15",
+		Border: "15", ActiveBorder: "11", Error: "9", Info: "14",
+		Failing: "9", Terminating: "11",
+	},
+}
+
+// MonochromeTheme is the theme name that disables color.
+const MonochromeTheme = "monochrome"
+
+// ApplyTheme rebuilds the styles for the named theme; an empty name means
+// "dark". NO_COLOR (https://no-color.org) overrides the choice with
+// monochrome.
+func ApplyTheme(name string) error {
+	if name == "" {
+		name = "dark"
+	}
+	if name != MonochromeTheme {
+		if _, ok := Themes[name]; !ok {
+			return fmt.Errorf("theme: unknown theme %q (available: %s)", name, strings.Join(ThemeNames(), ", "))
+		}
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		name = MonochromeTheme
+	}
+
+	if name == MonochromeTheme {
+		applyMonochrome()
+		return nil
+	}
+	applyPalette(Themes[name])
+	return nil
+}
+
+// ThemeNames lists the accepted theme names.
+func ThemeNames() []string {
+	names := []string{MonochromeTheme}
+	for name := range Themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func applyPalette(t Theme) {
 	TitleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("62")).
-			Padding(0, 1)
+		Bold(true).
+		Foreground(lipgloss.Color(t.Title)).
+		Padding(0, 1)
 
 	SelectedStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("229")).
-			Background(lipgloss.Color("57")).
-			Padding(0, 1)
+		Foreground(lipgloss.Color(t.SelectedFg)).
+		Background(lipgloss.Color(t.SelectedBg)).
+		Padding(0, 1)
 
 	NormalStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("245")).
-			Padding(0, 1)
+		Foreground(lipgloss.Color(t.Normal)).
+		Padding(0, 1)
 
 	PanelStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("62")).
-			Padding(1, 2)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(t.Border)).
+		Padding(1, 2)
+
+	ActivePanelStyle = PanelStyle.Copy().
+		BorderForeground(lipgloss.Color(t.ActiveBorder))
 
 	ErrorStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("196")).
-			Bold(true)
+		Foreground(lipgloss.Color(t.Error)).
+		Bold(true)
 
 	InfoStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("110")).
-			Bold(true)
-)
+		Foreground(lipgloss.Color(t.Info)).
+		Bold(true)
+
+	FailingStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color(t.Failing)).
+		Padding(0, 1)
+
+	TerminatingStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color(t.Terminating)).
+		Padding(0, 1)
+
+	BadgeStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color(t.SelectedFg)).
+		Background(lipgloss.Color(t.Error)).
+		Padding(0, 1)
+
+	ContextStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color(t.SelectedFg)).
+		Background(lipgloss.Color(t.Title)).
+		Padding(0, 1)
+
+	DisabledStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color(t.Normal)).
+		Faint(true)
+}
+
+// applyMonochrome conveys selection with reverse video and the active panel
+// with a heavier border, so nothing depends on color.
+func applyMonochrome() {
+	// NO_COLOR makes lipgloss drop to the ASCII profile, which also strips
+	// bold and reverse video. Plain ANSI keeps those without any color.
+	lipgloss.SetColorProfile(termenv.ANSI)
+
+	TitleStyle = lipgloss.NewStyle().
+		Bold(true).
+		Underline(true).
+		Padding(0, 1)
+
+	SelectedStyle = lipgloss.NewStyle().
+		Reverse(true).
+		Padding(0, 1)
+
+	NormalStyle = lipgloss.NewStyle().
+		Padding(0, 1)
+
+	PanelStyle = lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder()).
+		Padding(1, 2)
+
+	ActivePanelStyle = PanelStyle.Copy().
+		Border(lipgloss.DoubleBorder())
+
+	ErrorStyle = lipgloss.NewStyle().
+		Bold(true)
+
+	InfoStyle = lipgloss.NewStyle().
+		Bold(true)
+
+	FailingStyle = lipgloss.NewStyle().
+		Bold(true).
+		Padding(0, 1)
+
+	TerminatingStyle = lipgloss.NewStyle().
+		Faint(true).
+		Padding(0, 1)
+
+	BadgeStyle = lipgloss.NewStyle().
+		Bold(true).
+		Reverse(true).
+		Padding(0, 1)
+
+	ContextStyle = lipgloss.NewStyle().
+		Underline(true).
+		Padding(0, 1)
+
+	DisabledStyle = lipgloss.NewStyle().
+		Faint(true)
+}
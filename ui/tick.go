@@ -7,14 +7,16 @@ import (
 	"kubetbe/msg"
 )
 
-func Tick() tea.Cmd {
-	return tea.Tick(2*time.Second, func(t time.Time) tea.Msg {
+// Tick schedules the next refresh of namespaces, pods and logs.
+func Tick(interval time.Duration) tea.Cmd {
+	return tea.Tick(interval, func(t time.Time) tea.Msg {
 		return TickMsg{}
 	})
 }
 
-func StartLogLoadTimer(podName string) tea.Cmd {
-	return tea.Tick(3*time.Second, func(t time.Time) tea.Msg {
+// StartLogLoadTimer delays the first log fetch for a newly opened panel.
+func StartLogLoadTimer(podName string, delay time.Duration) tea.Cmd {
+	return tea.Tick(delay, func(t time.Time) tea.Msg {
 		return msg.StartLogLoadMsg{PodName: podName}
 	})
 }
@@ -1,21 +1,33 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 
 	"kubetbe/kubectl"
+	"kubetbe/msg"
 	"kubetbe/utils"
 )
 
 func (m *Model) Init() tea.Cmd {
 	// Start namespace watch by default
 	m.NamespaceWatch = true
+	if m.Config.StartupNamespace != "" {
+		return tea.Batch(
+			m.openNamespace("", m.Config.StartupNamespace, "", ""),
+			kubectl.FetchContexts(m.sessionContext(), m.kubeFlags()), // names the context in titles
+			tea.EnterAltScreen,
+		)
+	}
 	return tea.Batch(
-		kubectl.FetchNamespaces(m.SearchTerm),
-		Tick(),
+		m.fetchNamespaces(),
+		kubectl.FetchContexts(m.sessionContext(), m.kubeFlags()), // names the context in titles
+		Tick(m.Config.RefreshInterval),
 		tea.EnterAltScreen,
 	)
 }
@@ -39,26 +51,71 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case tea.KeyMsg:
+		if m.TypedConfirm != nil {
+			return m, m.handleTypedConfirmKey(msg)
+		}
+
+		if m.DeleteDialog != nil {
+			return m, m.handleDeleteDialogKey(msg)
+		}
+
+		if m.State == "namespace_select" && m.NSFilterActive {
+			handled := true
+			switch msg.Type {
+			case tea.KeyEnter:
+				m.NSFilterActive = false
+				m.SearchTerm = strings.TrimSpace(m.SearchTerm)
+				m.applyNamespaceFilter(true)
+			case tea.KeyEscape:
+				m.NSFilterActive = false
+				m.SearchTerm = m.NSFilterPrev
+				m.applyNamespaceFilter(true)
+			case tea.KeyCtrlU:
+				m.SearchTerm = ""
+				m.applyNamespaceFilter(true)
+			case tea.KeyBackspace, tea.KeyDelete:
+				if len(m.SearchTerm) > 0 {
+					runes := []rune(m.SearchTerm)
+					m.SearchTerm = string(runes[:len(runes)-1])
+					m.applyNamespaceFilter(true)
+				}
+			case tea.KeyRunes:
+				m.SearchTerm += string(msg.Runes)
+				m.applyNamespaceFilter(true)
+			case tea.KeyUp, tea.KeyDown:
+				// Let the arrow keys move through the filtered list while typing
+				handled = false
+			default:
+				handled = false
+			}
+			if handled {
+				return m, nil
+			}
+		}
+
 		if m.State == "namespace_select" && m.ServiceIPInputActive {
 			handled := true
 			switch msg.Type {
 			case tea.KeyEnter:
-				ip := strings.TrimSpace(m.ServiceIPQuery)
-				if ip == "" {
-					m.ServiceIPErr = fmt.Errorf("please enter an IP address")
+				query := strings.TrimSpace(m.ServiceIPQuery)
+				if query == "" {
+					m.ServiceIPErr = fmt.Errorf("please enter an IP, CIDR, DNS name or port")
+					m.ServiceIPListErr = nil
 					m.ServiceIPResult = nil
 					m.ServiceIPSearching = false
 				} else {
-					m.ServiceIPQuery = ip
+					m.ServiceIPQuery = query
 					m.ServiceIPSearching = true
 					m.ServiceIPErr = nil
+					m.ServiceIPListErr = nil
 					m.ServiceIPResult = nil
 					m.ServiceIPInputActive = false
-					return m, kubectl.FindServiceByIP(ip)
+					return m, m.findResource(query)
 				}
 			case tea.KeyEscape:
 				m.ServiceIPInputActive = false
 				m.ServiceIPErr = nil
+				m.ServiceIPListErr = nil
 				m.ServiceIPResult = nil
 				m.ServiceIPSearching = false
 				m.ServiceIPQuery = ""
@@ -77,23 +134,87 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
-		switch msg.String() {
-		case "ctrl+c", "q":
-			m.Quit = true
-			if m.State == "panel_view" {
-				// Stop all watch commands
-				if m.PodsPanel != nil && m.PodsPanel.UpdateCmd != nil {
-					m.PodsPanel.UpdateCmd.Process.Kill()
+		if m.State == "panel_view" && m.PodPatternActive {
+			if m.handlePodPatternKey(msg) {
+				return m, nil
+			}
+		}
+
+		action := m.Keys.Action(m.keyView(), msg.String())
+
+		if m.ShowHelp {
+			// Any key closes the help overlay; quit still quits
+			if action != ActionQuit {
+				m.ShowHelp = false
+				return m, nil
+			}
+		}
+
+		if action == ActionErrorInfo {
+			m.ErrorDetails = !m.ErrorDetails
+			return m, nil
+		}
+
+		if m.AuditPanel != nil && action != ActionQuit && action != ActionHelp {
+			return m, m.handleAuditKey(action)
+		}
+
+		if m.ContextPicker != nil && action != ActionQuit && action != ActionHelp && action != ActionAudit {
+			return m, m.handleContextKey(action)
+		}
+
+		if m.Diagnosis != nil && action != ActionQuit && action != ActionHelp && action != ActionAudit {
+			return m, m.handleDiagnosisKey(action)
+		}
+
+		if m.State == "namespace_select" && !m.ServiceIPSearching && len(m.ServiceIPResult) > 0 {
+			// Lookup results take over navigation until closed
+			switch action {
+			case ActionUp:
+				if m.ServiceIPCursor > 0 {
+					m.ServiceIPCursor--
 				}
-				for _, p := range m.LogsPanels {
-					if p.UpdateCmd != nil {
-						p.UpdateCmd.Process.Kill()
-					}
+				return m, nil
+			case ActionDown:
+				if m.ServiceIPCursor < len(m.ServiceIPResult)-1 {
+					m.ServiceIPCursor++
 				}
+				return m, nil
+			case ActionTop:
+				m.ServiceIPCursor = 0
+				return m, nil
+			case ActionBottom:
+				m.ServiceIPCursor = len(m.ServiceIPResult) - 1
+				return m, nil
+			case ActionOpen:
+				return m, m.openLookupResult(m.ServiceIPResult[m.ServiceIPCursor])
 			}
+		}
+
+		if m.Keys.Disabled(action) {
+			m.Err = fmt.Errorf("%s is disabled in read-only mode", action)
+			return m, nil
+		}
+		if why := m.denied(action); why != "" {
+			m.Err = errors.New(why)
+			return m, nil
+		}
+
+		switch action {
+		case ActionHelp:
+			m.ShowHelp = true
+
+		case ActionAudit:
+			m.ShowHelp = false
+			return m, m.openAudit()
+
+		case ActionQuit:
+			m.Quit = true
+			// Stop every kubectl call still running
+			m.Shutdown()
 			return m, tea.Quit
 
-		case "up", "k":
+		case ActionUp:
 			if m.State == "namespace_select" {
 				m.DeleteConfirmation = "" // Clear delete confirmation on navigation
 				m.moveNamespaceCursor(-1)
@@ -114,7 +235,7 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 
-		case "down", "j":
+		case ActionDown:
 			if m.State == "namespace_select" {
 				m.DeleteConfirmation = "" // Clear delete confirmation on navigation
 				m.moveNamespaceCursor(1)
@@ -137,92 +258,223 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 
-		case "enter":
-			if m.State == "namespace_select" && len(m.Namespaces) > 0 {
-				m.SelectedNS = m.Namespaces[m.Cursor]
-				m.State = "panel_view"
-				m.LogPageIndex = 0 // Reset to first page
-				m.PodCursor = 0
-				m.PodDeleteConfirmation = ""
-				m.DeletingPod = ""
-				m.DescribePanel = nil
-				m.DescribeTarget = ""
-				m.ServiceIPInputActive = false
-				// Initialize pods panel before starting watch
-				m.PodsPanel = &Panel{
-					Title:    fmt.Sprintf("Pods in %s", m.SelectedNS),
-					Content:  []string{"Loading pods..."},
-					MaxLines: m.Height / 3,
-					Watch:    true,
+		case ActionPageUp, ActionPageDown, ActionTop, ActionBottom:
+			if m.State == "namespace_select" {
+				m.DeleteConfirmation = ""
+				switch action {
+				case ActionPageUp:
+					m.changeNamespacePage(-1)
+				case ActionPageDown:
+					m.changeNamespacePage(1)
+				case ActionTop:
+					m.jumpNamespaceToStart()
+				case ActionBottom:
+					m.jumpNamespaceToEnd()
 				}
-				return m, tea.Batch(
-					kubectl.StartPodsWatch(m.SelectedNS),
-					Tick(),
-				)
+			} else if m.State == "panel_view" {
+				m.scrollActivePanel(action)
+			}
+
+		case ActionOpen:
+			if m.State == "namespace_select" && len(m.Namespaces) > 0 {
+				ns := m.Namespaces[m.Cursor]
+				return m, m.openNamespace(ns.Context, ns.Name, "", "")
 			}
 
-		case "r":
+		case ActionRefresh:
 			if m.State == "namespace_select" {
 				// Refresh namespace list
 				m.DeleteConfirmation = "" // Clear any pending delete confirmation
 				return m, tea.Batch(
-					kubectl.FetchNamespaces(m.SearchTerm),
-					Tick(), // Continue watch
+					m.fetchNamespaces(),
+					Tick(m.Config.RefreshInterval), // Continue watch
 				)
 			}
 
-		case "esc":
+		case ActionCancel:
 			if m.State == "namespace_select" {
 				m.ServiceIPInputActive = false
 				m.ServiceIPSearching = false
 				m.ServiceIPErr = nil
+				m.ServiceIPListErr = nil
 				m.ServiceIPResult = nil
 				m.ServiceIPQuery = ""
+			} else if m.State == "panel_view" {
+				// Dismiss a finished bulk delete first, then clear marks
+				m.BulkDeleteConfirm = false
+				if m.BulkDelete != nil && m.BulkDelete.Done {
+					m.BulkDelete = nil
+				} else {
+					m.MarkedPods = map[string]bool{}
+				}
 			}
 
-		case "f":
+		case ActionMark:
+			if m.State == "panel_view" && m.PodsPanel != nil {
+				selectedPod, _ := m.selectedPodAndList()
+				if selectedPod == "" {
+					break
+				}
+				if m.MarkedPods[selectedPod] {
+					delete(m.MarkedPods, selectedPod)
+				} else {
+					m.MarkedPods[selectedPod] = true
+				}
+			}
+
+		case ActionMarkMatch:
+			if m.State == "panel_view" && m.PodsPanel != nil {
+				m.PodPatternActive = true
+				m.PodPattern = ""
+				m.BulkDeleteConfirm = false
+			}
+
+		case ActionMarkStatus:
+			if m.State == "panel_view" && m.PodsPanel != nil {
+				selectedPod, _ := m.selectedPodAndList()
+				status := parsePodStatuses(m.PodsPanel.Content)[selectedPod]
+				if status == "" {
+					break
+				}
+				m.markPods(func(_, s string) bool { return s == status })
+			}
+
+		case ActionFilter:
+			if m.State == "namespace_select" {
+				m.NSFilterActive = true
+				m.NSFilterPrev = m.SearchTerm
+				m.ServiceIPInputActive = false
+				m.DeleteConfirmation = ""
+			}
+
+		case ActionDiagnose:
+			if m.State == "namespace_select" && len(m.Namespaces) > 0 {
+				return m, m.openDiagnosis(m.Namespaces[m.Cursor])
+			}
+
+		case ActionStar:
+			if m.State == "namespace_select" && len(m.Namespaces) > 0 {
+				m.UserState.ToggleFavorite(m.Namespaces[m.Cursor].Name)
+				if err := m.UserState.Save(); err != nil {
+					m.Err = err
+				}
+				m.applyNamespaceFilter(false)
+			}
+
+		case ActionFavorites:
+			if m.State == "namespace_select" {
+				m.FavoritesOnly = !m.FavoritesOnly
+				m.applyNamespaceFilter(false)
+			}
+
+		case ActionFind:
 			if m.State == "namespace_select" {
 				m.ServiceIPInputActive = true
 				m.ServiceIPSearching = false
 				m.ServiceIPErr = nil
+				m.ServiceIPListErr = nil
 			}
 
-		case "d":
+		case ActionDelete:
 			if m.State == "namespace_select" && len(m.Namespaces) > 0 {
 				if m.DeletingNamespace != "" {
 					// Already processing a delete; ignore additional delete requests
 					break
 				}
-				selectedNamespace := m.Namespaces[m.Cursor]
+				selected := m.Namespaces[m.Cursor]
+				selectedNamespace := selected.Name
+				f := m.namespaceFlags(selected)
+				if m.Config.IsProtected(selectedNamespace) {
+					// A second keypress is too easy for kube-system; make them type it
+					m.DeleteConfirmation = ""
+					m.TypedConfirm = &TypedConfirm{
+						What:     "Namespace",
+						Expected: selectedNamespace,
+						Run: func() tea.Cmd {
+							m.DeletingNamespace = selectedNamespace
+							return kubectl.DeleteNamespace(m.sessionContext(), f, selectedNamespace)
+						},
+					}
+					m.DeletePreview = &NamespaceDeletePreviewMsg{Namespace: selectedNamespace}
+					m.DeletePreviewLoading = true
+					return m, kubectl.PreviewNamespaceDelete(m.viewContext(), f, selectedNamespace)
+				}
 				// If already confirming, delete the namespace
-				if m.DeleteConfirmation == selectedNamespace {
+				if m.DeleteConfirmation == selectedNamespace && m.DeleteConfirmContext == selected.Context {
 					// Actually delete the namespace
 					m.DeletingNamespace = selectedNamespace
 					m.DeleteConfirmation = ""
-					return m, kubectl.DeleteNamespace(selectedNamespace)
+					return m, kubectl.DeleteNamespace(m.sessionContext(), f, selectedNamespace)
 				} else {
-					// Ask for confirmation
+					// Ask for confirmation, showing what the delete would remove
 					m.DeleteConfirmation = selectedNamespace
+					m.DeleteConfirmContext = selected.Context
+					m.DeletePreview = &NamespaceDeletePreviewMsg{Namespace: selectedNamespace}
+					m.DeletePreviewLoading = true
+					return m, kubectl.PreviewNamespaceDelete(m.viewContext(), f, selectedNamespace)
 				}
 			} else if m.State == "panel_view" && m.PodsPanel != nil {
-				if m.DeletingPod != "" {
+				if m.DeletingPod != "" || m.bulkDeleteRunning() {
 					// Already processing a delete; ignore additional requests
 					break
 				}
+				if len(m.MarkedPods) > 0 {
+					namespace := m.SelectedNS
+					pods := m.markedPods()
+					m.PodDeleteConfirmation = ""
+					if m.Config.IsProtected(namespace) {
+						m.BulkDeleteConfirm = false
+						m.TypedConfirm = &TypedConfirm{
+							What:     "Namespace",
+							Expected: namespace,
+							Verb:     fmt.Sprintf("delete %d marked pods in it", len(pods)),
+							Run: func() tea.Cmd {
+								return m.startBulkDelete(namespace, pods, kubectl.DeleteOptions{})
+							},
+						}
+						break
+					}
+					if m.BulkDeleteConfirm {
+						return m, m.startBulkDelete(namespace, pods, kubectl.DeleteOptions{})
+					}
+					m.BulkDeleteConfirm = true
+					break
+				}
 				selectedPod, podNames := m.selectedPodAndList()
 				if len(podNames) == 0 || selectedPod == "" {
 					m.PodCursor = 0
 					break
 				}
+				if m.Config.IsProtected(m.SelectedNS) {
+					namespace := m.SelectedNS
+					m.PodDeleteConfirmation = ""
+					m.TypedConfirm = &TypedConfirm{
+						What:     fmt.Sprintf("Pod in namespace '%s'", namespace),
+						Expected: selectedPod,
+						Run: func() tea.Cmd {
+							m.DeletingPod = selectedPod
+							return kubectl.DeletePod(m.sessionContext(), m.kubeFlags(), namespace, selectedPod, kubectl.DeleteOptions{})
+						},
+					}
+					break
+				}
 				if m.PodDeleteConfirmation == selectedPod {
 					m.DeletingPod = selectedPod
 					m.PodDeleteConfirmation = ""
-					return m, kubectl.DeletePod(m.SelectedNS, selectedPod)
+					return m, kubectl.DeletePod(m.sessionContext(), m.kubeFlags(), m.SelectedNS, selectedPod, kubectl.DeleteOptions{})
 				}
 				m.PodDeleteConfirmation = selectedPod
 			}
 
-		case "i":
+		case ActionDeleteWith:
+			if m.State == "panel_view" && m.PodsPanel != nil {
+				if m.DeletingPod != "" || m.bulkDeleteRunning() {
+					break
+				}
+				return m, m.openDeleteDialog()
+			}
+
+		case ActionDescribe:
 			if m.State == "panel_view" && m.PodsPanel != nil {
 				selectedPod, podNames := m.selectedPodAndList()
 				if len(podNames) == 0 || selectedPod == "" {
@@ -255,13 +507,11 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					Watch:     false,
 				}
 				m.ActivePanel = 1
-				return m, kubectl.DescribePod(m.SelectedNS, selectedPod)
+				return m, kubectl.DescribePod(m.viewContext(), m.kubeFlags(), m.SelectedNS, selectedPod)
 			}
 
-		case "tab":
-			if m.State == "namespace_select" {
-				m.moveNamespaceCursor(1)
-			} else if m.State == "panel_view" {
+		case ActionNextPanel:
+			if m.State == "panel_view" {
 				if m.DescribePanel != nil {
 					if len(m.LogsPanels) == 0 {
 						m.DescribePanel = nil
@@ -319,23 +569,21 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 							// Create new log panel and start timer for delayed log loading (3 seconds)
 							newPanel := &Panel{
 								Title:     "Logs: " + targetPodName,
-								Content:   []string{"Loading logs..."},
+								Content:   []string{logsLoading},
 								MaxLines:  20,
 								ScrollPos: 0,
 								Watch:     true,
 							}
 							m.LogsPanels = append(m.LogsPanels, newPanel)
 							m.PendingLogLoad = targetPodName
-							return m, StartLogLoadTimer(targetPodName)
+							return m, StartLogLoadTimer(targetPodName, m.Config.LogLoadDelay)
 						}
 					}
 				}
 			}
 
-		case "shift+tab":
-			if m.State == "namespace_select" {
-				m.moveNamespaceCursor(-1)
-			} else if m.State == "panel_view" {
+		case ActionPrevPanel:
+			if m.State == "panel_view" {
 				if m.DescribePanel != nil && m.ActivePanel == 1 {
 					m.DescribePanel = nil
 					m.DescribeTarget = ""
@@ -385,82 +633,85 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 							// Create new log panel and start timer for delayed log loading (3 seconds)
 							newPanel := &Panel{
 								Title:     "Logs: " + targetPodName,
-								Content:   []string{"Loading logs..."},
+								Content:   []string{logsLoading},
 								MaxLines:  20,
 								ScrollPos: 0,
 								Watch:     true,
 							}
 							m.LogsPanels = append(m.LogsPanels, newPanel)
 							m.PendingLogLoad = targetPodName
-							return m, StartLogLoadTimer(targetPodName)
+							return m, StartLogLoadTimer(targetPodName, m.Config.LogLoadDelay)
 						}
 					}
 				}
 			}
 
-		case "right", "l":
+		case ActionNextNSPage:
 			if m.State == "namespace_select" {
 				m.changeNamespacePage(1)
 			}
 
-		case "left", "h":
+		case ActionPrevNSPage:
 			if m.State == "namespace_select" {
 				m.changeNamespacePage(-1)
 			}
 
-		case "b":
+		case ActionBack:
 			if m.State == "panel_view" {
-				m.State = "namespace_select"
-				m.LogPageIndex = 0 // Reset to first page
-				m.PodCursor = 0
-				m.PodDeleteConfirmation = ""
-				m.DeletingPod = ""
-				m.DescribePanel = nil
-				m.DescribeTarget = ""
-				// Stop all watch commands
-				if m.PodsPanel != nil && m.PodsPanel.UpdateCmd != nil {
-					m.PodsPanel.UpdateCmd.Process.Kill()
-					m.PodsPanel.UpdateCmd = nil
-				}
-				for _, p := range m.LogsPanels {
-					if p.UpdateCmd != nil {
-						p.UpdateCmd.Process.Kill()
-						p.UpdateCmd = nil
-					}
-				}
-				m.PodsPanel = nil
-				m.LogsPanels = []*Panel{}
-				m.ActivePanel = 0
-				return m, tea.Batch(
-					kubectl.FetchNamespaces(m.SearchTerm),
-					Tick(), // Continue namespace watch
-				)
+				return m, m.closeNamespace()
 			}
 
-		// Clear delete confirmation on any other key press (except d)
+		case ActionContext:
+			return m, m.openContextPicker()
+
+		// Clear delete confirmation on any other key press (except delete)
 		default:
 			if m.State == "namespace_select" && m.DeleteConfirmation != "" {
 				// Only clear if it's not a navigation key we already handle
-				if msg.String() != "d" && msg.String() != "enter" && msg.String() != "q" && msg.String() != "r" {
+				if action != ActionDelete && action != ActionOpen && action != ActionQuit && action != ActionRefresh {
 					m.DeleteConfirmation = ""
 				}
 			}
 			if m.State == "panel_view" && m.PodDeleteConfirmation != "" {
-				if msg.String() != "d" && msg.String() != "tab" && msg.String() != "shift+tab" {
+				if action != ActionDelete && action != ActionNextPanel && action != ActionPrevPanel {
 					m.PodDeleteConfirmation = ""
 				}
 			}
+			if m.State == "panel_view" && action != ActionDelete {
+				m.BulkDeleteConfirm = false
+			}
 		}
 
 	case NamespaceListMsg:
-		m.Namespaces = msg.Namespaces
-		if len(m.Namespaces) > 0 && m.Cursor >= len(m.Namespaces) {
-			m.Cursor = len(m.Namespaces) - 1
+		// Drop a listing that was started before a context switch
+		if m.multiCluster() != (msg.Clusters != nil) || (!m.multiCluster() && msg.Context != m.KubeContext) {
+			return m, nil
+		}
+		m.NamespacesFetching = false
+		m.NamespacesErr = nil
+		m.NamespacesUpdated = time.Now()
+		namespaces := msg.Namespaces
+		if m.multiCluster() {
+			namespaces = m.keepStaleClusters(msg)
+		}
+		m.AllNamespaces = namespaces
+		m.Clusters = msg.Clusters
+		m.applyNamespaceFilter(false)
+
+	case ContextListMsg:
+		m.applyContexts(msg)
+
+	case AccessMsg:
+		// The answers are cached by kubectl; drop a confirmation they rule out
+		if m.State == "panel_view" && msg.Namespace == m.SelectedNS && m.denied(ActionDelete) != "" {
+			m.PodDeleteConfirmation = ""
+			m.BulkDeleteConfirm = false
 		}
-		m.updateNamespacePagination()
 
 	case ErrorMsg:
-		m.Err = msg.Err
+		// Keep the last good namespace list; it is marked stale
+		m.NamespacesFetching = false
+		m.NamespacesErr = msg.Err
 
 	case NamespaceDeleteMsg:
 		m.DeleteConfirmation = "" // Clear confirmation after delete attempt
@@ -472,11 +723,18 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		} else {
 			// Successfully deleted, refresh namespace list
 			return m, tea.Batch(
-				kubectl.FetchNamespaces(m.SearchTerm),
-				Tick(), // Continue namespace watch
+				m.fetchNamespaces(),
+				Tick(m.Config.RefreshInterval), // Continue namespace watch
 			)
 		}
 
+	case NamespaceDeletePreviewMsg:
+		// Ignore a preview for a confirmation that has since moved on
+		if m.DeletePreview != nil && m.DeletePreview.Namespace == msg.Namespace {
+			m.DeletePreview = &msg
+			m.DeletePreviewLoading = false
+		}
+
 	case PodDeleteMsg:
 		m.PodDeleteConfirmation = ""
 		if m.DeletingPod == msg.Pod {
@@ -486,6 +744,49 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.Err = msg.Err
 		}
 
+	case NamespaceDiagnosisMsg:
+		m.applyDiagnosis(msg)
+
+	case FinalizersClearedMsg:
+		if d := m.Diagnosis; d != nil && d.Namespace == msg.Namespace {
+			if msg.Err != nil {
+				d.Status, d.StatusErr = msg.Err.Error(), true
+				return m, nil
+			}
+			d.Status, d.StatusErr = fmt.Sprintf("Cleared finalizers on %s", msg.Ref), false
+			return m, kubectl.DiagnoseNamespace(m.viewContext(), d.flags, d.Namespace)
+		}
+
+	case DisruptionCheckMsg:
+		if d := m.DeleteDialog; d != nil && d.Namespace == msg.Namespace && slices.Equal(d.Pods, msg.Pods) {
+			d.Checking = false
+			d.Violations = msg.Violations
+			d.CheckErr = msg.Err
+		}
+
+	case BulkDeleteMsg:
+		if b := m.BulkDelete; b != nil && b.Namespace == msg.Namespace {
+			switch {
+			case msg.Started:
+				b.Progress[msg.Pod] = bulkDeleting
+			case msg.Err != nil:
+				b.Progress[msg.Pod] = bulkFailed
+				b.Errs[msg.Pod] = msg.Err
+			default:
+				b.Progress[msg.Pod] = bulkDeleted
+			}
+			if m.SelectedNS == msg.Namespace && b.Progress[msg.Pod] == bulkDeleted {
+				delete(m.MarkedPods, msg.Pod)
+			}
+			return m, kubectl.WaitBulkDelete(b.Namespace, b.updates)
+		}
+
+	case BulkDeleteDoneMsg:
+		// Failed pods stay marked so the delete can be retried
+		if b := m.BulkDelete; b != nil && b.Namespace == msg.Namespace {
+			b.Done = true
+		}
+
 	case PodDescribeMsg:
 		if msg.Err != nil {
 			m.Err = msg.Err
@@ -495,17 +796,28 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.DescribePanel.ScrollPos = 0
 		}
 
+	case AuditLogMsg:
+		if m.AuditPanel != nil {
+			if msg.Err != nil {
+				m.AuditPanel.Content = []string{ErrorStyle.Render(fmt.Sprintf("Error: %v", msg.Err))}
+			} else {
+				m.AuditPanel.Content = auditLines(msg.Entries)
+			}
+			m.AuditPanel.ScrollPos = 0
+		}
+
 	case ServiceLookupMsg:
 		m.ServiceIPSearching = false
+		m.ServiceIPCursor = 0
+		m.ServiceIPListErr = msg.ListErr
 		if msg.Err != nil {
 			m.ServiceIPErr = msg.Err
 			m.ServiceIPResult = nil
 		} else {
 			m.ServiceIPErr = nil
-			if len(msg.Result) == 0 {
-				m.ServiceIPResult = []string{fmt.Sprintf("No service found for IP %s", msg.IP)}
-			} else {
-				m.ServiceIPResult = msg.Result
+			m.ServiceIPResult = msg.Matches
+			if len(msg.Matches) == 0 {
+				m.ServiceIPErr = fmt.Errorf("nothing found for %s", msg.Query)
 			}
 		}
 
@@ -519,8 +831,15 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				Watch:    true,
 			}
 		}
+		m.PodsPanel.Fetching = false
+		if msg.Err != nil {
+			// Keep the last good listing; it is marked stale
+			m.PodsPanel.Err = msg.Err
+			break
+		}
+		m.PodsPanel.Err = nil
+		m.PodsPanel.Updated = time.Now()
 		// CRITICAL: Limit pods panel content to prevent overflow
-		// podsPanelHeight is 12, so max content lines = 12 - 5 (border/padding/title) = 7
 		// Allow more content since we have scroll support
 		podsContent := msg.Content
 		// Don't limit content - let scroll handle it
@@ -540,7 +859,7 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.PodsPanel.ScrollPos > utils.Max(0, len(podsContent)-m.PodsPanel.MaxLines) {
 			m.PodsPanel.ScrollPos = utils.Max(0, len(podsContent)-m.PodsPanel.MaxLines)
 		}
-		m.Err = msg.Err
+		m.Err = nil
 
 		// Parse pods and start log watching for each
 		if msg.Err == nil {
@@ -601,6 +920,13 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Update available pods list
 			m.AvailablePods = podNames
 
+			// Forget marks on pods that are gone
+			for pod := range m.MarkedPods {
+				if !slices.Contains(podNames, pod) {
+					delete(m.MarkedPods, pod)
+				}
+			}
+
 			// Clean up log panels for pods that no longer exist
 			var validLogPanels []*Panel
 			for _, p := range m.LogsPanels {
@@ -616,10 +942,7 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					validLogPanels = append(validLogPanels, p)
 				} else {
 					// Stop watching logs for deleted pods
-					if p.UpdateCmd != nil {
-						p.UpdateCmd.Process.Kill()
-						p.UpdateCmd = nil
-					}
+					p.stop()
 				}
 			}
 			m.LogsPanels = validLogPanels
@@ -629,7 +952,7 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				firstPod := podNames[0]
 				newPanel := &Panel{
 					Title:     "Logs: " + firstPod,
-					Content:   []string{"Loading logs..."},
+					Content:   []string{logsLoading},
 					MaxLines:  20,
 					ScrollPos: 0,
 					Watch:     true,
@@ -637,14 +960,30 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.LogsPanels = append(m.LogsPanels, newPanel)
 				m.ActivePanel = 1 // Switch to log panel
 				m.PendingLogLoad = firstPod
-				return m, StartLogLoadTimer(firstPod)
+				return m, StartLogLoadTimer(firstPod, m.Config.LogLoadDelay)
 			}
 		}
 
 	case LogUpdateMsg:
+		if msg.Err != nil {
+			for _, p := range m.LogsPanels {
+				if strings.TrimPrefix(p.Title, "Logs: ") == msg.PodName {
+					// Keep the logs already shown; the error goes below
+					p.Fetching = false
+					p.Err = msg.Err
+					if len(p.Content) == 1 && p.Content[0] == logsLoading {
+						p.Content = []string{"No logs: see the error below."}
+					}
+					break
+				}
+			}
+		}
 		if msg.Err == nil {
 			for i, p := range m.LogsPanels {
-				if strings.Contains(p.Title, msg.PodName) {
+				if strings.TrimPrefix(p.Title, "Logs: ") == msg.PodName {
+					m.LogsPanels[i].Fetching = false
+					m.LogsPanels[i].Err = nil
+					m.LogsPanels[i].Updated = time.Now()
 					// Store all log content - renderPanel will handle truncation based on maxHeight
 					// This allows scrolling through more logs
 					m.LogsPanels[i].Content = msg.Content
@@ -664,32 +1003,43 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// 3 seconds have passed, start loading logs for the pending pod
 		if m.PendingLogLoad == msg.PodName {
 			m.PendingLogLoad = ""
-			return m, kubectl.StartLogWatch(msg.PodName, m.SelectedNS)
+			return m, kubectl.StartLogWatch(m.logContext(msg.PodName), m.kubeFlags(), msg.PodName, m.SelectedNS, m.logOptions())
 		}
 
 	case TickMsg:
 		var cmds []tea.Cmd
 
-		// Refresh namespace list if watching
+		// Refresh what is watched. A fetch still running, e.g. retrying an
+		// unreachable cluster, is left to finish instead of being joined by
+		// another one; the ticks go on regardless.
+		watching := false
 		if m.State == "namespace_select" && m.NamespaceWatch {
-			cmds = append(cmds, kubectl.FetchNamespaces(m.SearchTerm))
+			watching = true
+			if !m.NamespacesFetching {
+				cmds = append(cmds, m.fetchNamespaces())
+			}
 		}
 
 		// Refresh pods and logs if in panel view
 		if m.State == "panel_view" && m.PodsPanel != nil && m.PodsPanel.Watch {
-			cmds = append(cmds, kubectl.StartPodsWatch(m.SelectedNS))
+			watching = true
+			if !m.PodsPanel.Fetching {
+				m.PodsPanel.Fetching = true
+				cmds = append(cmds, kubectl.StartPodsWatch(m.panelContext(m.PodsPanel), m.kubeFlags(), m.SelectedNS, m.PodSelector))
+			}
 
 			// Only refresh logs for panels that are already loaded (lazy loading)
 			for _, logPanel := range m.LogsPanels {
-				if logPanel.Watch {
+				if logPanel.Watch && !logPanel.Fetching {
+					logPanel.Fetching = true
 					podName := strings.TrimPrefix(logPanel.Title, "Logs: ")
-					cmds = append(cmds, kubectl.StartLogWatch(podName, m.SelectedNS))
+					cmds = append(cmds, kubectl.StartLogWatch(m.panelContext(logPanel), m.kubeFlags(), podName, m.SelectedNS, m.logOptions()))
 				}
 			}
 		}
 
-		if len(cmds) > 0 {
-			return m, tea.Batch(append(cmds, Tick())...)
+		if watching {
+			return m, tea.Batch(append(cmds, Tick(m.Config.RefreshInterval))...)
 		}
 		return m, nil
 	}
@@ -697,6 +1047,155 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// closeNamespace leaves panel_view for the namespace list.
+func (m *Model) closeNamespace() tea.Cmd {
+	m.State = "namespace_select"
+	m.NamespaceContext = ""
+	m.LogPageIndex = 0 // Reset to first page
+	m.PodCursor = 0
+	m.PodDeleteConfirmation = ""
+	m.DeletingPod = ""
+	m.MarkedPods = map[string]bool{}
+	m.BulkDeleteConfirm = false
+	m.DescribePanel = nil
+	m.DescribeTarget = ""
+	m.PodSelector = ""
+	// Stop all watch commands and whatever else the namespace started
+	m.leaveView()
+	m.PodsPanel = nil
+	m.LogsPanels = []*Panel{}
+	m.ActivePanel = 0
+	return tea.Batch(
+		m.fetchNamespaces(),
+		Tick(m.Config.RefreshInterval), // Continue namespace watch
+	)
+}
+
+// openNamespace switches to panel_view for namespace. kubeContext is the
+// cluster it was found in, in multi-cluster mode: the namespace's calls go
+// there until it is closed. selector filters the pods panel; focusPod, if
+// set, gets its logs opened right away instead of waiting for the first pod
+// in the list.
+func (m *Model) openNamespace(kubeContext, namespace, selector, focusPod string) tea.Cmd {
+	// Calls for the namespace list or a previous namespace are of no use now
+	m.leaveView()
+	m.NamespaceContext = kubeContext
+	m.SelectedNS = namespace
+	m.PodSelector = selector
+	m.State = "panel_view"
+	m.LogPageIndex = 0 // Reset to first page
+	m.PodCursor = 0
+	m.PodDeleteConfirmation = ""
+	m.DeletingPod = ""
+	m.MarkedPods = map[string]bool{}
+	m.BulkDeleteConfirm = false
+	if !m.bulkDeleteRunning() {
+		m.BulkDelete = nil
+	}
+	m.DescribePanel = nil
+	m.DescribeTarget = ""
+	m.ServiceIPInputActive = false
+	m.LogsPanels = []*Panel{}
+	m.ActivePanel = 0
+
+	title := fmt.Sprintf("Pods in %s", m.SelectedNS)
+	if selector != "" {
+		title += fmt.Sprintf(" [%s]", selector)
+	}
+	// Initialize pods panel before starting watch
+	m.PodsPanel = &Panel{
+		Title:    title,
+		Content:  []string{"Loading pods..."},
+		MaxLines: m.Height / 3,
+		Watch:    true,
+	}
+
+	cmds := []tea.Cmd{
+		kubectl.StartPodsWatch(m.panelContext(m.PodsPanel), m.kubeFlags(), m.SelectedNS, m.PodSelector),
+		Tick(m.Config.RefreshInterval),
+	}
+	if !m.Config.ReadOnly {
+		// Find out up front which deletes RBAC would refuse
+		cmds = append(cmds, kubectl.CheckAccess(m.viewContext(), m.kubeFlags(), namespace))
+	}
+	if focusPod != "" {
+		m.LogsPanels = append(m.LogsPanels, &Panel{
+			Title:     "Logs: " + focusPod,
+			Content:   []string{logsLoading},
+			MaxLines:  20,
+			ScrollPos: 0,
+			Watch:     true,
+		})
+		m.ActivePanel = 1
+		cmds = append(cmds, kubectl.StartLogWatch(m.logContext(focusPod), m.kubeFlags(), focusPod, m.SelectedNS, m.logOptions()))
+	}
+	return tea.Batch(cmds...)
+}
+
+// openLookupResult jumps from a lookup match to the namespace it lives in.
+// Services open with the pods panel filtered by their selector, so the
+// first backing pod's logs load automatically; pods and endpoint addresses
+// open with that pod's logs.
+func (m *Model) openLookupResult(r msg.LookupMatch) tea.Cmd {
+	if r.Namespace == "" {
+		m.ServiceIPErr = fmt.Errorf("%s/%s is not namespaced", r.Kind, r.Name)
+		return nil
+	}
+	switch r.Kind {
+	case "Service":
+		return m.openNamespace(r.Context, r.Namespace, r.Selector, "")
+	case "Pod":
+		return m.openNamespace(r.Context, r.Namespace, "", r.Name)
+	case "Endpoints":
+		if pod, ok := strings.CutPrefix(r.Owner, "Pod/"); ok {
+			return m.openNamespace(r.Context, r.Namespace, "", pod)
+		}
+	}
+	return m.openNamespace(r.Context, r.Namespace, "", "")
+}
+
+// scrollActivePanel pages or jumps within the active log or describe panel.
+func (m *Model) scrollActivePanel(action Action) {
+	var p *Panel
+	if m.DescribePanel != nil && m.ActivePanel == 1 {
+		p = m.DescribePanel
+	} else if logIndex := m.activeLogPanelIndex(); logIndex >= 0 && logIndex < len(m.LogsPanels) {
+		p = m.LogsPanels[logIndex]
+	}
+	if p == nil {
+		return
+	}
+	scrollPanel(p, action)
+}
+
+// scrollPanel moves p by a line, a page or to either end.
+func scrollPanel(p *Panel, action Action) {
+	maxScroll := utils.Max(0, len(p.Content)-p.MaxLines)
+	switch action {
+	case ActionUp:
+		p.ScrollPos = utils.Max(0, p.ScrollPos-1)
+	case ActionDown:
+		p.ScrollPos = utils.Min(maxScroll, p.ScrollPos+1)
+	case ActionPageUp:
+		p.ScrollPos = utils.Max(0, p.ScrollPos-p.MaxLines)
+	case ActionPageDown:
+		p.ScrollPos = utils.Min(maxScroll, p.ScrollPos+p.MaxLines)
+	case ActionTop:
+		p.ScrollPos = 0
+	case ActionBottom:
+		p.ScrollPos = maxScroll
+	}
+}
+
+// logOptions converts the configured log defaults for the kubectl layer.
+func (m *Model) logOptions() kubectl.LogOptions {
+	return kubectl.LogOptions{
+		Tail:       m.Config.Logs.Tail,
+		Timestamps: m.Config.Logs.Timestamps,
+		Since:      m.Config.Logs.Since,
+	}
+}
+
 func (m *Model) activeLogPanelIndex() int {
 	if len(m.LogsPanels) == 0 {
 		return -1
@@ -774,14 +1273,40 @@ func (m *Model) totalPanelCount() int {
 	return count
 }
 
+// applyNamespaceFilter rebuilds Namespaces from AllNamespaces using
+// SearchTerm. When resetCursor is set (the filter text changed) the cursor
+// jumps to the best match; otherwise it stays on the same namespace.
+func (m *Model) applyNamespaceFilter(resetCursor bool) {
+	var selected msg.NamespaceInfo
+	if m.Cursor >= 0 && m.Cursor < len(m.Namespaces) {
+		selected = m.Namespaces[m.Cursor]
+	}
+
+	m.Namespaces = FilterNamespaces(m.AllNamespaces, m.SearchTerm, m.UserState.IsFavorite, m.FavoritesOnly)
+
+	m.Cursor = 0
+	if !resetCursor {
+		for i, ns := range m.Namespaces {
+			if ns.Name == selected.Name && ns.Context == selected.Context {
+				m.Cursor = i
+				break
+			}
+		}
+	}
+	m.updateNamespacePagination()
+}
+
 func (m *Model) visibleNamespacesPerPage() int {
-	// fixed max 10 per page, adjust for very small terminals
-	lines := m.Height - 8
+	// capped by namespace_page_size, adjust for very small terminals
+	lines := m.Height - 9 // title, table header, footer
+	if m.NSFilterActive || m.SearchTerm != "" {
+		lines -= 2 // filter line
+	}
 	if lines < 3 {
 		lines = 3
 	}
-	if lines > 10 {
-		lines = 10
+	if lines > m.Config.NamespacePageSize {
+		lines = m.Config.NamespacePageSize
 	}
 	return lines
 }
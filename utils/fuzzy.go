@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// FuzzyScore reports whether every rune of pattern appears in s in order
+// (case-insensitive) and how good the match is. Higher scores rank first:
+// contiguous runs, matches at the start of s or after a separator, and
+// shorter candidates all score higher. An empty pattern matches everything
+// with score 0.
+func FuzzyScore(pattern, s string) (int, bool) {
+	if pattern == "" {
+		return 0, true
+	}
+	p := []rune(strings.ToLower(pattern))
+	t := []rune(strings.ToLower(s))
+
+	score := 0
+	pi := 0
+	lastMatch := -1
+	for ti := 0; ti < len(t) && pi < len(p); ti++ {
+		if t[ti] != p[pi] {
+			continue
+		}
+		score += 1
+		switch {
+		case ti == 0:
+			score += 8
+		case isSeparator(t[ti-1]):
+			score += 6
+		}
+		if lastMatch >= 0 {
+			if ti == lastMatch+1 {
+				score += 5
+			} else {
+				score -= Min(ti-lastMatch-1, 3)
+			}
+		}
+		lastMatch = ti
+		pi++
+	}
+	if pi < len(p) {
+		return 0, false
+	}
+
+	// An exact substring beats a scattered match of the same pattern
+	if strings.Contains(string(t), string(p)) {
+		score += 10
+	}
+	score -= utf8.RuneCountInString(s) / 8
+	return score, true
+}
+
+// FuzzyRank returns the indexes of the names that fuzzy-match term,
+// pinned ones first and then best match first. Ties, and every name when
+// term is empty, go in name order.
+func FuzzyRank(term string, names []string, pinned func(i int) bool) []int {
+	term = strings.TrimSpace(term)
+	type ranked struct {
+		index  int
+		pinned bool
+		score  int
+	}
+	var matches []ranked
+	for i, name := range names {
+		if score, ok := FuzzyScore(term, name); ok {
+			matches = append(matches, ranked{i, pinned(i), score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.pinned != b.pinned {
+			return a.pinned
+		}
+		if a.score != b.score {
+			return a.score > b.score
+		}
+		return names[a.index] < names[b.index]
+	})
+
+	order := make([]int, len(matches))
+	for i, r := range matches {
+		order[i] = r.index
+	}
+	return order
+}
+
+func isSeparator(r rune) bool {
+	return r == '-' || r == '_' || r == '.' || r == '/' || r == ' '
+}
@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFuzzyScore(t *testing.T) {
+	tests := []struct {
+		pattern, s string
+		ok         bool
+	}{
+		{"", "anything", true},
+		{"pay", "payments", true},
+		{"PAY", "payments", true},
+		{"pmt", "payments", true},
+		{"tmp", "payments", false},
+		{"paymentss", "payments", false},
+	}
+	for _, tt := range tests {
+		if _, ok := FuzzyScore(tt.pattern, tt.s); ok != tt.ok {
+			t.Errorf("FuzzyScore(%q, %q) matched = %v, want %v", tt.pattern, tt.s, ok, tt.ok)
+		}
+	}
+}
+
+func TestFuzzyScoreOrder(t *testing.T) {
+	// Each pair: the first candidate must score higher than the second
+	tests := []struct {
+		pattern, better, worse string
+	}{
+		{"pay", "payments", "api-gateway"},        // start of the name over the middle
+		{"gw", "api-gw", "go-away"},               // contiguous over scattered
+		{"web", "shop-web", "weeb"},               // after a separator, exact substring
+		{"shop", "shop", "shop-payments-staging"}, // shorter over longer
+	}
+	for _, tt := range tests {
+		better, ok1 := FuzzyScore(tt.pattern, tt.better)
+		worse, ok2 := FuzzyScore(tt.pattern, tt.worse)
+		if !ok1 || !ok2 {
+			t.Fatalf("%q should match both %q and %q", tt.pattern, tt.better, tt.worse)
+		}
+		if better <= worse {
+			t.Errorf("FuzzyScore(%q): %q scored %d, not above %q at %d", tt.pattern, tt.better, better, tt.worse, worse)
+		}
+	}
+}
+
+func TestFuzzyRank(t *testing.T) {
+	names := []string{"shop-web", "payments", "api-gateway", "kube-system", "shop-db", "pay"}
+	none := func(int) bool { return false }
+	tests := []struct {
+		name   string
+		term   string
+		pinned func(i int) bool
+		want   []string
+	}{
+		{"empty term is name order", "", none,
+			[]string{"api-gateway", "kube-system", "pay", "payments", "shop-db", "shop-web"}},
+		{"blank term is empty", "  ", none,
+			[]string{"api-gateway", "kube-system", "pay", "payments", "shop-db", "shop-web"}},
+		{"best match first", "pay", none, []string{"pay", "payments", "api-gateway"}},
+		{"ties in name order", "shop", none, []string{"shop-db", "shop-web"}},
+		{"no match", "zzz", none, []string{}},
+		{"pinned first", "pay", func(i int) bool { return names[i] == "api-gateway" },
+			[]string{"api-gateway", "pay", "payments"}},
+		{"pinned in name order without a term", "", func(i int) bool { return names[i] == "shop-web" || names[i] == "kube-system" },
+			[]string{"kube-system", "shop-web", "api-gateway", "pay", "payments", "shop-db"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []string{}
+			for _, i := range FuzzyRank(tt.term, names, tt.pinned) {
+				got = append(got, names[i])
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FuzzyRank(%q) = %v, want %v", tt.term, got, tt.want)
+			}
+		})
+	}
+}
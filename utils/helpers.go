@@ -1,5 +1,11 @@
 package utils
 
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
 func Min(a, b int) int {
 	if a < b {
 		return a
@@ -21,3 +27,39 @@ func Abs(x int) int {
 	return x
 }
 
+// FirstLine returns the first non-blank line of s, trimmed, or s itself if
+// every line is blank. It keeps multi-line kubectl output and errors to one
+// line in summaries.
+func FirstLine(s string) string {
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return s
+}
+
+// OrDash returns s, or "-" when s is blank, for table cells.
+func OrDash(s string) string {
+	if strings.TrimSpace(s) == "" {
+		return "-"
+	}
+	return s
+}
+
+// HumanDuration formats d the way kubectl prints ages: 45s, 12m, 5h, 3d, 2y.
+func HumanDuration(d time.Duration) string {
+	switch {
+	case d < 0:
+		return "0s"
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	case d < 2*365*24*time.Hour:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+	return fmt.Sprintf("%dy", int(d.Hours()/24/365))
+}